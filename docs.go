@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"terraform-provider-caiac/lib"
+	"terraform-provider-caiac/lib/docs"
+)
+
+const docsUsage = `Usage: terraform-provider-caiac docs [flags]
+
+Generate the provider's documentation from its schemas and examples, in the
+layout used by the Terraform Registry.
+
+Flags:
+`
+
+// generateDocs implements the docs subcommand, returning the process's exit
+// code.
+func generateDocs(ctx context.Context, args []string) int {
+	flags := flag.NewFlagSet("docs", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), docsUsage)
+		flags.PrintDefaults()
+	}
+	dir := flags.String("dir", "docs", "the directory to write documentation to")
+	examples := flags.String("examples", "examples", "the directory holding examples, laid out as for tfplugindocs")
+	check := flags.Bool("check", false, "don't write anything; exit with status 1 if any page is out of date")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	pages, err := docs.Generate(ctx, caiac.New(), *examples)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	names := []string{}
+	for name := range pages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	stale := false
+	for _, name := range names {
+		filename := filepath.Join(*dir, filepath.FromSlash(name))
+
+		if *check {
+			if existing, err := os.ReadFile(filename); err != nil || string(existing) != string(pages[name]) {
+				fmt.Printf("%s is out of date\n", filename)
+				stale = true
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if err := os.WriteFile(filename, pages[name], 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	if stale {
+		return 1
+	}
+	return 0
+}
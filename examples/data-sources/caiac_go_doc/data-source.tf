@@ -0,0 +1,8 @@
+data "caiac_go_doc" "greet" {
+  directory   = "greet"
+  import_path = "example.com/greet"
+}
+
+output "greet_docs" {
+  value = data.caiac_go_doc.greet.markdown
+}
@@ -0,0 +1,11 @@
+data "caiac_go_imports_graph" "module" {
+  directory = "."
+}
+
+# Fail the plan if the module has any import cycles.
+check "no_import_cycles" {
+  assert {
+    condition     = length(data.caiac_go_imports_graph.module.cycles) == 0
+    error_message = "Import cycles: ${jsonencode(data.caiac_go_imports_graph.module.cycles)}"
+  }
+}
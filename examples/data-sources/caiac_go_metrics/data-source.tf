@@ -0,0 +1,10 @@
+data "caiac_go_metrics" "greet" {
+  path = "greet"
+}
+
+output "most_complex" {
+  value = [
+    for f in data.caiac_go_metrics.greet.functions : f.name
+    if f.complexity == data.caiac_go_metrics.greet.max_complexity
+  ]
+}
@@ -0,0 +1,5 @@
+data "caiac_go_module" "root" {}
+
+output "module_path" {
+  value = data.caiac_go_module.root.module_path
+}
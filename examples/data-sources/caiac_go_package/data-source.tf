@@ -0,0 +1,7 @@
+data "caiac_go_package" "greet" {
+  directory = "greet"
+}
+
+output "greet_api" {
+  value = data.caiac_go_package.greet.api
+}
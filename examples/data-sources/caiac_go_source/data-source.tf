@@ -0,0 +1,7 @@
+data "caiac_go_source" "main" {
+  filename = "main.go"
+}
+
+output "main_imports" {
+  value = [for i in data.caiac_go_source.main.imports : i.path]
+}
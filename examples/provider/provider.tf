@@ -0,0 +1,5 @@
+provider "caiac" {
+  # Paths in resources and data sources are relative to this directory.
+  # Defaults to $CAIAC_BASE_DIR, or the current working directory.
+  base_dir = "${path.root}/src"
+}
@@ -0,0 +1,20 @@
+resource "caiac_go_module" "example" {
+  directory   = "."
+  module_path = "example.com/greet"
+  go_version  = "1.21"
+
+  require {
+    path    = "golang.org/x/text"
+    version = "v0.14.0"
+  }
+
+  replace {
+    old_path = "golang.org/x/text"
+    new_path = "../text"
+  }
+
+  retract {
+    low       = "v1.0.0"
+    rationale = "Published accidentally."
+  }
+}
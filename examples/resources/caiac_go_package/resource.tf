@@ -0,0 +1,36 @@
+resource "caiac_go_package" "greet" {
+  directory    = "greet"
+  package_name = "greet"
+  doc          = "Package greet says hello."
+
+  file {
+    name = "greet.go"
+
+    import {
+      path = "fmt"
+    }
+
+    func {
+      name = "Print"
+
+      body {
+        statement {
+          kind = "expression"
+          expression {
+            kind = "call"
+            call {
+              func {
+                from = "fmt"
+                prop = "Println"
+              }
+              arg {
+                kind  = "string"
+                value = "Hello, world!"
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}
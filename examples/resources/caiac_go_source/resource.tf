@@ -0,0 +1,102 @@
+resource "caiac_go_source" "greet" {
+  filename     = "greet/greet.go"
+  package_name = "greet"
+
+  import {
+    path = "fmt"
+  }
+
+  func {
+    name = "Hello"
+
+    signature {
+      param {
+        name = "name"
+        type = "string"
+      }
+      result {
+        type = "string"
+      }
+    }
+
+    body {
+      statement {
+        kind = "return"
+        expression {
+          kind = "call"
+          call {
+            func {
+              from = "fmt"
+              prop = "Sprintf"
+            }
+            arg {
+              kind  = "string"
+              value = "Hello, %s!"
+            }
+            arg {
+              kind  = "identifier"
+              value = "name"
+            }
+          }
+        }
+      }
+    }
+  }
+
+  func {
+    name = "PrintWorld"
+
+    body {
+      statement {
+        kind = "expression"
+        expression {
+          kind = "call"
+          call {
+            func {
+              from = "fmt"
+              prop = "Println"
+            }
+            arg {
+              kind  = "string"
+              value = "Hello, world!"
+            }
+          }
+        }
+      }
+    }
+  }
+
+  # A function without a body is implemented elsewhere, e.g. in assembly.
+  func {
+    name = "now"
+
+    signature {
+      result {
+        type = "int64"
+      }
+    }
+  }
+
+  func {
+    name = "Version"
+
+    signature {
+      result {
+        type = "int"
+      }
+    }
+
+    body {
+      statement {
+        kind = "return"
+        expression {
+          kind = "literal"
+          literal {
+            kind  = "int"
+            value = "1"
+          }
+        }
+      }
+    }
+  }
+}
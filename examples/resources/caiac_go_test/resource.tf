@@ -0,0 +1,53 @@
+resource "caiac_go_test" "greet" {
+  filename     = "greet/greet_test.go"
+  package_name = "greet"
+  external     = true
+
+  import {
+    path = "example.com/greet"
+  }
+
+  example {
+    name   = "Print"
+    output = "Hello, world!"
+
+    body {
+      statement {
+        kind = "expression"
+        expression {
+          kind = "call"
+          call {
+            func {
+              from = "greet"
+              prop = "Print"
+            }
+          }
+        }
+      }
+    }
+  }
+
+  table_test {
+    name = "Hello"
+    func = "greet.Hello"
+
+    param {
+      name = "who"
+      type = "string"
+    }
+    result {
+      type = "string"
+    }
+
+    case {
+      name = "world"
+      args = ["\"world\""]
+      want = ["\"Hello, world!\""]
+    }
+    case {
+      name = "empty"
+      args = ["\"\""]
+      want = ["\"Hello, !\""]
+    }
+  }
+}
@@ -0,0 +1,12 @@
+resource "caiac_go_test_run" "greet" {
+  directory = "."
+  packages  = ["./greet/..."]
+  flags     = ["-race", "-count=1"]
+  timeout   = "5m"
+
+  # Re-run the tests whenever the code under test changes.
+  triggers = {
+    source = caiac_go_source.greet.contents
+    tests  = caiac_go_test.greet.contents
+  }
+}
@@ -0,0 +1,13 @@
+resource "caiac_go_workspace" "example" {
+  directory  = "."
+  go_version = "1.21"
+
+  use {
+    path        = "./greet"
+    module_path = "example.com/greet"
+  }
+
+  use {
+    path = "./tools"
+  }
+}
@@ -0,0 +1,307 @@
+package datasources
+
+import (
+	"go/ast"
+	"go/token"
+	gotypes "go/types"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type astImport struct {
+	Name types.String `tfsdk:"name"`
+	Path types.String `tfsdk:"path"`
+}
+
+type astField struct {
+	Name types.String `tfsdk:"name"`
+	Type types.String `tfsdk:"type"`
+}
+
+type astFunc struct {
+	Name      types.String `tfsdk:"name"`
+	Receiver  types.String `tfsdk:"receiver"`
+	Exported  types.Bool   `tfsdk:"exported"`
+	Signature types.String `tfsdk:"signature"`
+	Params    []astField   `tfsdk:"params"`
+	Results   []astField   `tfsdk:"results"`
+}
+
+type astType struct {
+	Name     types.String `tfsdk:"name"`
+	Kind     types.String `tfsdk:"kind"`
+	Exported types.Bool   `tfsdk:"exported"`
+	Type     types.String `tfsdk:"type"`
+	Fields   []astField   `tfsdk:"fields"`
+}
+
+type astValue struct {
+	Name     types.String `tfsdk:"name"`
+	Exported types.Bool   `tfsdk:"exported"`
+	Type     types.String `tfsdk:"type"`
+	Value    types.String `tfsdk:"value"`
+}
+
+var astFieldAttributes = map[string]schema.Attribute{
+	"name": schema.StringAttribute{
+		Computed:    true,
+		Description: "The field's name, or null if it's unnamed or embedded.",
+	},
+	"type": schema.StringAttribute{
+		Computed:    true,
+		Description: "The field's type, as written in the source.",
+	},
+}
+
+// astAttributes describes the parsed structure of a Go source file.
+var astAttributes = map[string]schema.Attribute{
+	"package_name": schema.StringAttribute{
+		Computed:    true,
+		Description: "The name in the file's package clause.",
+	},
+	"imports": schema.ListNestedAttribute{
+		Computed:    true,
+		Description: "The file's imports, in source order.",
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"name": schema.StringAttribute{
+					Computed:    true,
+					Description: "The import's explicit name, or null if it has none.",
+				},
+				"path": schema.StringAttribute{
+					Computed:    true,
+					Description: "The imported package's path.",
+				},
+			},
+		},
+	},
+	"funcs": schema.ListNestedAttribute{
+		Computed:    true,
+		Description: "Top-level functions and methods, in source order.",
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"name": schema.StringAttribute{
+					Computed:    true,
+					Description: "The function's name.",
+				},
+				"receiver": schema.StringAttribute{
+					Computed:    true,
+					Description: "The receiver's type for methods, or null for plain functions.",
+				},
+				"exported": schema.BoolAttribute{
+					Computed:    true,
+					Description: "Whether the function is exported.",
+				},
+				"signature": schema.StringAttribute{
+					Computed:    true,
+					Description: "The function's type, e.g. \"func(w http.ResponseWriter, r *http.Request)\".",
+				},
+				"params": schema.ListNestedAttribute{
+					Computed:     true,
+					Description:  "The function's parameters.",
+					NestedObject: schema.NestedAttributeObject{Attributes: astFieldAttributes},
+				},
+				"results": schema.ListNestedAttribute{
+					Computed:     true,
+					Description:  "The function's results.",
+					NestedObject: schema.NestedAttributeObject{Attributes: astFieldAttributes},
+				},
+			},
+		},
+	},
+	"types": schema.ListNestedAttribute{
+		Computed:    true,
+		Description: "Top-level type declarations, in source order.",
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"name": schema.StringAttribute{
+					Computed:    true,
+					Description: "The type's name.",
+				},
+				"kind": schema.StringAttribute{
+					Computed:    true,
+					Description: "One of \"struct\", \"interface\", \"alias\", or \"other\".",
+				},
+				"exported": schema.BoolAttribute{
+					Computed:    true,
+					Description: "Whether the type is exported.",
+				},
+				"type": schema.StringAttribute{
+					Computed:    true,
+					Description: "The type's underlying type expression, as written in the source.",
+				},
+				"fields": schema.ListNestedAttribute{
+					Computed:     true,
+					Description:  "Struct fields or interface methods. Empty for other kinds.",
+					NestedObject: schema.NestedAttributeObject{Attributes: astFieldAttributes},
+				},
+			},
+		},
+	},
+	"consts": schema.ListNestedAttribute{
+		Computed:     true,
+		Description:  "Top-level constants, in source order.",
+		NestedObject: schema.NestedAttributeObject{Attributes: astValueAttributes},
+	},
+	"vars": schema.ListNestedAttribute{
+		Computed:     true,
+		Description:  "Top-level variables, in source order.",
+		NestedObject: schema.NestedAttributeObject{Attributes: astValueAttributes},
+	},
+}
+
+var astValueAttributes = map[string]schema.Attribute{
+	"name": schema.StringAttribute{
+		Computed:    true,
+		Description: "The declared name.",
+	},
+	"exported": schema.BoolAttribute{
+		Computed:    true,
+		Description: "Whether the name is exported.",
+	},
+	"type": schema.StringAttribute{
+		Computed:    true,
+		Description: "The declared type, or null if it's inferred.",
+	},
+	"value": schema.StringAttribute{
+		Computed:    true,
+		Description: "The initializer expression, or null if there isn't one.",
+	},
+}
+
+// describeFile fills model's structural attributes from a parsed file.
+func describeFile(f *ast.File, model *goSourceDataSourceModel) {
+	model.PackageName = types.StringValue(f.Name.Name)
+	model.Imports = []astImport{}
+	model.Funcs = []astFunc{}
+	model.Types = []astType{}
+	model.Consts = []astValue{}
+	model.Vars = []astValue{}
+
+	for _, spec := range f.Imports {
+		imp := astImport{Name: types.StringNull(), Path: types.StringValue(spec.Path.Value)}
+		if p, err := strconv.Unquote(spec.Path.Value); err == nil {
+			imp.Path = types.StringValue(p)
+		}
+		if spec.Name != nil {
+			imp.Name = types.StringValue(spec.Name.Name)
+		}
+		model.Imports = append(model.Imports, imp)
+	}
+
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			model.Funcs = append(model.Funcs, describeFunc(decl))
+		case *ast.GenDecl:
+			switch decl.Tok {
+			case token.TYPE:
+				for _, spec := range decl.Specs {
+					model.Types = append(model.Types, describeType(spec.(*ast.TypeSpec)))
+				}
+			case token.CONST:
+				model.Consts = append(model.Consts, describeValues(decl)...)
+			case token.VAR:
+				model.Vars = append(model.Vars, describeValues(decl)...)
+			}
+		}
+	}
+}
+
+func describeFunc(decl *ast.FuncDecl) astFunc {
+	fn := astFunc{
+		Name:      types.StringValue(decl.Name.Name),
+		Receiver:  types.StringNull(),
+		Exported:  types.BoolValue(decl.Name.IsExported()),
+		Signature: types.StringValue(gotypes.ExprString(decl.Type)),
+		Params:    describeFields(decl.Type.Params),
+		Results:   describeFields(decl.Type.Results),
+	}
+	if decl.Recv != nil && len(decl.Recv.List) > 0 {
+		fn.Receiver = types.StringValue(gotypes.ExprString(decl.Recv.List[0].Type))
+	}
+	return fn
+}
+
+func describeType(spec *ast.TypeSpec) astType {
+	t := astType{
+		Name:     types.StringValue(spec.Name.Name),
+		Kind:     types.StringValue(typeKind(spec)),
+		Exported: types.BoolValue(spec.Name.IsExported()),
+		Type:     types.StringValue(gotypes.ExprString(spec.Type)),
+		Fields:   []astField{},
+	}
+
+	switch typ := spec.Type.(type) {
+	case *ast.StructType:
+		t.Fields = describeFields(typ.Fields)
+	case *ast.InterfaceType:
+		t.Fields = describeFields(typ.Methods)
+	}
+
+	return t
+}
+
+func typeKind(spec *ast.TypeSpec) string {
+	if spec.Assign.IsValid() {
+		return "alias"
+	}
+
+	switch spec.Type.(type) {
+	case *ast.StructType:
+		return "struct"
+	case *ast.InterfaceType:
+		return "interface"
+	default:
+		return "other"
+	}
+}
+
+// describeFields flattens a field list so that each name gets its own entry.
+func describeFields(fields *ast.FieldList) []astField {
+	res := []astField{}
+	if fields == nil {
+		return res
+	}
+
+	for _, field := range fields.List {
+		typ := types.StringValue(gotypes.ExprString(field.Type))
+		if len(field.Names) == 0 {
+			res = append(res, astField{Name: types.StringNull(), Type: typ})
+			continue
+		}
+		for _, name := range field.Names {
+			res = append(res, astField{Name: types.StringValue(name.Name), Type: typ})
+		}
+	}
+
+	return res
+}
+
+func describeValues(decl *ast.GenDecl) []astValue {
+	res := []astValue{}
+	for _, spec := range decl.Specs {
+		spec := spec.(*ast.ValueSpec)
+
+		typ := types.StringNull()
+		if spec.Type != nil {
+			typ = types.StringValue(gotypes.ExprString(spec.Type))
+		}
+
+		for i, name := range spec.Names {
+			value := types.StringNull()
+			if i < len(spec.Values) {
+				value = types.StringValue(gotypes.ExprString(spec.Values[i]))
+			}
+			res = append(res, astValue{
+				Name:     types.StringValue(name.Name),
+				Exported: types.BoolValue(name.IsExported()),
+				Type:     typ,
+				Value:    value,
+			})
+		}
+	}
+	return res
+}
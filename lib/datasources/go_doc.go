@@ -0,0 +1,256 @@
+package datasources
+
+import (
+	"context"
+	"go/ast"
+	"go/doc"
+	"go/doc/comment"
+	"go/parser"
+	"go/token"
+	"html"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &goDocDataSource{}
+	_ datasource.DataSourceWithConfigure = &goDocDataSource{}
+)
+
+func NewGoDocDataSource() datasource.DataSource {
+	return &goDocDataSource{}
+}
+
+type goDocDataSourceModel struct {
+	Directory   types.String `tfsdk:"directory"`
+	PackageName types.String `tfsdk:"package_name"`
+	ImportPath  types.String `tfsdk:"import_path"`
+	RenderHTML  types.Bool   `tfsdk:"render_html"`
+	Markdown    types.String `tfsdk:"markdown"`
+	HTML        types.String `tfsdk:"html"`
+}
+
+type goDocDataSource struct {
+	baseDir string
+}
+
+func (d *goDocDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	dsd, ok := req.ProviderData.(*DataSourceData)
+	if !ok {
+		return
+	}
+	d.baseDir = dsd.BaseDir
+}
+
+func (d *goDocDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_go_doc"
+}
+
+func (d *goDocDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Renders a package's documentation, in the same order as go doc, as Markdown and optionally HTML.",
+		Attributes: map[string]schema.Attribute{
+			"directory": schema.StringAttribute{
+				Required:    true,
+				Description: "The package's directory, relative to the provider's base directory.",
+			},
+			"package_name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The package to document, if the directory holds more than one.",
+			},
+			"import_path": schema.StringAttribute{
+				Optional:    true,
+				Description: "The package's import path, shown in the rendered documentation. Defaults to directory.",
+			},
+			"render_html": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Also render the documentation as HTML, into html.",
+			},
+			"markdown": schema.StringAttribute{
+				Computed:    true,
+				Description: "The package's documentation rendered as Markdown.",
+			},
+			"html": schema.StringAttribute{
+				Computed:    true,
+				Description: "The package's documentation rendered as an HTML fragment, if render_html is set.",
+			},
+		},
+	}
+}
+
+func (d *goDocDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state goDocDataSourceModel
+
+	{
+		diags := req.Config.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+		if diags.HasError() {
+			return
+		}
+	}
+
+	dir := filepath.Join(d.baseDir, state.Directory.ValueString())
+
+	ctx = tflog.SetField(ctx, "directory", state.Directory.ValueString())
+	ctx = tflog.SetField(ctx, "path", dir)
+	tflog.Debug(ctx, "Rendering package documentation")
+
+	fset, files, err := parsePackage(dir, state.PackageName.ValueString(), parser.ParseComments)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to parse package",
+			err.Error(),
+		)
+		return
+	}
+
+	importPath := state.Directory.ValueString()
+	if !state.ImportPath.IsNull() {
+		importPath = state.ImportPath.ValueString()
+	}
+
+	pkg, err := doc.NewFromFiles(fset, files, importPath)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read package documentation",
+			err.Error(),
+		)
+		return
+	}
+
+	state.PackageName = types.StringValue(pkg.Name)
+	state.Markdown = types.StringValue(renderDoc(fset, pkg, &markdownWriter{}))
+	state.HTML = types.StringNull()
+	if state.RenderHTML.ValueBool() {
+		state.HTML = types.StringValue(renderDoc(fset, pkg, &htmlWriter{}))
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// docWriter abstracts over the output formats supported by renderDoc.
+type docWriter interface {
+	heading(level int, text string)
+	code(src string)
+	comment(p *comment.Printer, d *comment.Doc)
+	String() string
+}
+
+// renderDoc renders a package's documentation in the same order as go doc:
+// package comment, constants, variables, functions, then types along with
+// their associated declarations. Headings within a doc comment are nested
+// under the heading of whatever it documents.
+func renderDoc(fset *token.FileSet, pkg *doc.Package, w docWriter) string {
+	p := pkg.Printer()
+	text := func(level int, s string) {
+		if s != "" {
+			p.HeadingLevel = level + 1
+			w.comment(p, pkg.Parser().Parse(s))
+		}
+	}
+	values := func(level int, values []*doc.Value) {
+		for _, v := range values {
+			w.code(printNode(fset, v.Decl))
+			text(level, v.Doc)
+		}
+	}
+	funcs := func(level int, funcs []*doc.Func) {
+		for _, fn := range funcs {
+			title := "func " + fn.Name
+			if fn.Recv != "" {
+				title = "func (" + fn.Recv + ") " + fn.Name
+			}
+			w.heading(level, title)
+
+			decl := *fn.Decl
+			decl.Doc = nil
+			decl.Body = nil
+			w.code(printNode(fset, &decl))
+			text(level, fn.Doc)
+		}
+	}
+
+	w.heading(1, "package "+pkg.Name)
+	w.code(`import "` + pkg.ImportPath + `"`)
+	text(1, pkg.Doc)
+
+	if len(pkg.Consts) > 0 {
+		w.heading(2, "Constants")
+		values(2, pkg.Consts)
+	}
+	if len(pkg.Vars) > 0 {
+		w.heading(2, "Variables")
+		values(2, pkg.Vars)
+	}
+	if len(pkg.Funcs) > 0 {
+		w.heading(2, "Functions")
+		funcs(3, pkg.Funcs)
+	}
+	if len(pkg.Types) > 0 {
+		w.heading(2, "Types")
+		for _, t := range pkg.Types {
+			w.heading(3, "type "+t.Name)
+			w.code(printNode(fset, withoutDoc(t.Decl)))
+			text(3, t.Doc)
+			values(3, t.Consts)
+			values(3, t.Vars)
+			funcs(4, t.Funcs)
+			funcs(4, t.Methods)
+		}
+	}
+
+	return strings.TrimRight(w.String(), "\n") + "\n"
+}
+
+// withoutDoc returns a copy of decl without its doc comment, which is rendered
+// separately.
+func withoutDoc(decl *ast.GenDecl) *ast.GenDecl {
+	d := *decl
+	d.Doc = nil
+	return &d
+}
+
+type markdownWriter struct {
+	strings.Builder
+}
+
+func (w *markdownWriter) heading(level int, text string) {
+	w.WriteString(strings.Repeat("#", level) + " " + text + "\n\n")
+}
+
+func (w *markdownWriter) code(src string) {
+	w.WriteString("```go\n" + src + "\n```\n\n")
+}
+
+func (w *markdownWriter) comment(p *comment.Printer, d *comment.Doc) {
+	w.Write(p.Markdown(d))
+	w.WriteString("\n")
+}
+
+type htmlWriter struct {
+	strings.Builder
+}
+
+func (w *htmlWriter) heading(level int, text string) {
+	tag := "h" + string(rune('0'+level))
+	w.WriteString("<" + tag + ">" + html.EscapeString(text) + "</" + tag + ">\n")
+}
+
+func (w *htmlWriter) code(src string) {
+	w.WriteString("<pre><code>" + html.EscapeString(src) + "</code></pre>\n")
+}
+
+func (w *htmlWriter) comment(p *comment.Printer, d *comment.Doc) {
+	w.Write(p.HTML(d))
+}
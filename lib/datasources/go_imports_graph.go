@@ -0,0 +1,344 @@
+package datasources
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/mod/modfile"
+)
+
+var (
+	_ datasource.DataSource              = &goImportsGraphDataSource{}
+	_ datasource.DataSourceWithConfigure = &goImportsGraphDataSource{}
+)
+
+func NewGoImportsGraphDataSource() datasource.DataSource {
+	return &goImportsGraphDataSource{}
+}
+
+type goImportsGraphDataSourceModel struct {
+	Directory    types.String   `tfsdk:"directory"`
+	IncludeTests types.Bool     `tfsdk:"include_tests"`
+	ModulePath   types.String   `tfsdk:"module_path"`
+	Packages     []graphPackage `tfsdk:"packages"`
+	Edges        []graphEdge    `tfsdk:"edges"`
+	Cycles       [][]string     `tfsdk:"cycles"`
+}
+
+type graphPackage struct {
+	Path    types.String `tfsdk:"path"`
+	Dir     types.String `tfsdk:"dir"`
+	Imports []string     `tfsdk:"imports"`
+}
+
+type graphEdge struct {
+	From types.String `tfsdk:"from"`
+	To   types.String `tfsdk:"to"`
+}
+
+type goImportsGraphDataSource struct {
+	baseDir string
+}
+
+func (d *goImportsGraphDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	dsd, ok := req.ProviderData.(*DataSourceData)
+	if !ok {
+		return
+	}
+	d.baseDir = dsd.BaseDir
+}
+
+func (d *goImportsGraphDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_go_imports_graph"
+}
+
+func (d *goImportsGraphDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads the import graph of every package under a directory, reporting import cycles. Nested modules, in subdirectories with their own go.mod, are skipped.",
+		Attributes: map[string]schema.Attribute{
+			"directory": schema.StringAttribute{
+				Optional:    true,
+				Description: "The directory to walk, relative to the provider's base directory. Defaults to the base directory itself.",
+			},
+			"include_tests": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether imports from _test.go files are included.",
+			},
+			"module_path": schema.StringAttribute{
+				Computed:    true,
+				Description: "The module path from the directory's go.mod, used to name packages. Null if there's no go.mod, in which case packages are named by their directory.",
+			},
+			"packages": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Every package found under the directory, sorted by import path.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"path": schema.StringAttribute{
+							Computed:    true,
+							Description: "The package's import path.",
+						},
+						"dir": schema.StringAttribute{
+							Computed:    true,
+							Description: "The package's directory, relative to the walked directory.",
+						},
+						"imports": schema.ListAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "Every package imported by the package, sorted.",
+						},
+					},
+				},
+			},
+			"edges": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Imports between packages found under the directory.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"from": schema.StringAttribute{
+							Computed:    true,
+							Description: "The importing package.",
+						},
+						"to": schema.StringAttribute{
+							Computed:    true,
+							Description: "The imported package.",
+						},
+					},
+				},
+			},
+			"cycles": schema.ListAttribute{
+				ElementType: types.ListType{ElemType: types.StringType},
+				Computed:    true,
+				Description: "Groups of packages that import each other, directly or transitively. Empty if the graph is acyclic.",
+			},
+		},
+	}
+}
+
+func (d *goImportsGraphDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state goImportsGraphDataSourceModel
+
+	{
+		diags := req.Config.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+		if diags.HasError() {
+			return
+		}
+	}
+
+	root := filepath.Join(d.baseDir, state.Directory.ValueString())
+
+	ctx = tflog.SetField(ctx, "path", root)
+	tflog.Debug(ctx, "Walking import graph")
+
+	state.ModulePath = types.StringNull()
+	if data, err := os.ReadFile(filepath.Join(root, "go.mod")); err == nil {
+		if modulePath := modfile.ModulePath(data); modulePath != "" {
+			state.ModulePath = types.StringValue(modulePath)
+		}
+	}
+
+	imports, err := collectImports(root, state.IncludeTests.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to walk packages",
+			err.Error(),
+		)
+		return
+	}
+
+	// Name each package by its import path, so that edges can be matched
+	// against the imports that refer to them.
+	importPath := func(dir string) string {
+		if state.ModulePath.IsNull() {
+			return dir
+		}
+		if dir == "." {
+			return state.ModulePath.ValueString()
+		}
+		return path.Join(state.ModulePath.ValueString(), dir)
+	}
+
+	dirs := []string{}
+	for dir := range imports {
+		dirs = append(dirs, dir)
+	}
+	sort.Slice(dirs, func(i, j int) bool { return importPath(dirs[i]) < importPath(dirs[j]) })
+
+	local := map[string]bool{}
+	for _, dir := range dirs {
+		local[importPath(dir)] = true
+	}
+
+	graph := map[string][]string{}
+	state.Packages = []graphPackage{}
+	state.Edges = []graphEdge{}
+	for _, dir := range dirs {
+		from := importPath(dir)
+		state.Packages = append(state.Packages, graphPackage{
+			Path:    types.StringValue(from),
+			Dir:     types.StringValue(dir),
+			Imports: imports[dir],
+		})
+
+		for _, to := range imports[dir] {
+			if !local[to] || to == from {
+				continue
+			}
+			graph[from] = append(graph[from], to)
+			state.Edges = append(state.Edges, graphEdge{From: types.StringValue(from), To: types.StringValue(to)})
+		}
+	}
+
+	state.Cycles = findCycles(graph)
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// collectImports walks root and returns the sorted, deduplicated imports of
+// each directory containing Go files, keyed by slash-separated path relative
+// to root. Like the go command, it skips testdata, vendor, directories
+// starting with "." or "_", and nested modules.
+func collectImports(root string, includeTests bool) (map[string][]string, error) {
+	fset := token.NewFileSet()
+	seen := map[string]map[string]bool{}
+
+	err := filepath.WalkDir(root, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name := entry.Name()
+		if entry.IsDir() {
+			if p == root {
+				return nil
+			}
+			if name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			// A nested module's packages belong to that module, not this one.
+			if _, err := os.Stat(filepath.Join(p, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if filepath.Ext(name) != ".go" || (!includeTests && strings.HasSuffix(name, "_test.go")) {
+			return nil
+		}
+
+		f, err := parser.ParseFile(fset, p, nil, parser.ImportsOnly)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, filepath.Dir(p))
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if seen[rel] == nil {
+			seen[rel] = map[string]bool{}
+		}
+		for _, spec := range f.Imports {
+			if imp, err := strconv.Unquote(spec.Path.Value); err == nil {
+				seen[rel][imp] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := map[string][]string{}
+	for dir, imps := range seen {
+		res[dir] = []string{}
+		for imp := range imps {
+			res[dir] = append(res[dir], imp)
+		}
+		sort.Strings(res[dir])
+	}
+	return res, nil
+}
+
+// findCycles returns the strongly connected components of graph with more
+// than one package, using Tarjan's algorithm. Each cycle and the list of
+// cycles are sorted so the result is stable.
+func findCycles(graph map[string][]string) [][]string {
+	nodes := []string{}
+	for node := range graph {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	index := map[string]int{}
+	lowlink := map[string]int{}
+	onStack := map[string]bool{}
+	stack := []string{}
+	cycles := [][]string{}
+
+	var visit func(node string)
+	visit = func(node string) {
+		index[node] = len(index)
+		lowlink[node] = index[node]
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, next := range graph[node] {
+			if _, ok := index[next]; !ok {
+				visit(next)
+				if lowlink[next] < lowlink[node] {
+					lowlink[node] = lowlink[next]
+				}
+			} else if onStack[next] && index[next] < lowlink[node] {
+				lowlink[node] = index[next]
+			}
+		}
+
+		if lowlink[node] != index[node] {
+			return
+		}
+
+		component := []string{}
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == node {
+				break
+			}
+		}
+		if len(component) > 1 {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, node := range nodes {
+		if _, ok := index[node]; !ok {
+			visit(node)
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+	return cycles
+}
@@ -0,0 +1,340 @@
+package datasources
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	gotypes "go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &goMetricsDataSource{}
+	_ datasource.DataSourceWithConfigure = &goMetricsDataSource{}
+)
+
+func NewGoMetricsDataSource() datasource.DataSource {
+	return &goMetricsDataSource{}
+}
+
+type goMetricsDataSourceModel struct {
+	Path          types.String  `tfsdk:"path"`
+	IncludeTests  types.Bool    `tfsdk:"include_tests"`
+	Functions     []funcMetrics `tfsdk:"functions"`
+	MaxComplexity types.Int64   `tfsdk:"max_complexity"`
+	MaxDepth      types.Int64   `tfsdk:"max_depth"`
+	TotalLines    types.Int64   `tfsdk:"total_lines"`
+}
+
+type funcMetrics struct {
+	File       types.String `tfsdk:"file"`
+	Name       types.String `tfsdk:"name"`
+	Line       types.Int64  `tfsdk:"line"`
+	Lines      types.Int64  `tfsdk:"lines"`
+	Params     types.Int64  `tfsdk:"params"`
+	Results    types.Int64  `tfsdk:"results"`
+	Complexity types.Int64  `tfsdk:"complexity"`
+	Depth      types.Int64  `tfsdk:"depth"`
+}
+
+type goMetricsDataSource struct {
+	baseDir string
+}
+
+func (d *goMetricsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	dsd, ok := req.ProviderData.(*DataSourceData)
+	if !ok {
+		return
+	}
+	d.baseDir = dsd.BaseDir
+}
+
+func (d *goMetricsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_go_metrics"
+}
+
+func (d *goMetricsDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Measures the size and complexity of the functions in a Go file or directory.",
+		Attributes: map[string]schema.Attribute{
+			"path": schema.StringAttribute{
+				Required:    true,
+				Description: "A Go file, or a directory of Go files, relative to the provider's base directory.",
+			},
+			"include_tests": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether _test.go files in a directory are measured.",
+			},
+			"functions": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Metrics for every function and method, sorted by file and position.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"file": schema.StringAttribute{
+							Computed:    true,
+							Description: "The file declaring the function, relative to path.",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The function's name. Methods are qualified by their receiver, e.g. \"(*T).Name\".",
+						},
+						"line": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The line the declaration starts on.",
+						},
+						"lines": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The number of lines spanned by the declaration.",
+						},
+						"params": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The number of parameters.",
+						},
+						"results": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The number of results.",
+						},
+						"complexity": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The cyclomatic complexity: one plus the number of branches and short-circuiting operators.",
+						},
+						"depth": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The deepest nesting of control-flow statements.",
+						},
+					},
+				},
+			},
+			"max_complexity": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The highest complexity of any function.",
+			},
+			"max_depth": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The deepest nesting in any function.",
+			},
+			"total_lines": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The number of lines spanned by all functions.",
+			},
+		},
+	}
+}
+
+func (d *goMetricsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state goMetricsDataSourceModel
+
+	{
+		diags := req.Config.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+		if diags.HasError() {
+			return
+		}
+	}
+
+	root := filepath.Join(d.baseDir, state.Path.ValueString())
+
+	ctx = tflog.SetField(ctx, "path", root)
+	tflog.Debug(ctx, "Measuring functions")
+
+	root, files, err := metricsFiles(root, state.IncludeTests.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to find Go files",
+			err.Error(),
+		)
+		return
+	}
+
+	fset := token.NewFileSet()
+	state.Functions = []funcMetrics{}
+	var maxComplexity, maxDepth, totalLines int64
+
+	for _, filename := range files {
+		f, err := parser.ParseFile(fset, filepath.Join(root, filename), nil, parser.SkipObjectResolution)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to parse Go source",
+				err.Error(),
+			)
+			return
+		}
+
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+
+			m := measureFunc(fset, fn)
+			m.File = types.StringValue(filepath.ToSlash(filename))
+			state.Functions = append(state.Functions, m)
+
+			maxComplexity = max64(maxComplexity, m.Complexity.ValueInt64())
+			maxDepth = max64(maxDepth, m.Depth.ValueInt64())
+			totalLines += m.Lines.ValueInt64()
+		}
+	}
+
+	state.MaxComplexity = types.Int64Value(maxComplexity)
+	state.MaxDepth = types.Int64Value(maxDepth)
+	state.TotalLines = types.Int64Value(totalLines)
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// metricsFiles returns the directory holding the Go files to measure, along
+// with their names. If root is a file, that's the only one.
+func metricsFiles(root string, includeTests bool) (string, []string, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return "", nil, err
+	}
+	if !info.IsDir() {
+		return filepath.Dir(root), []string{filepath.Base(root)}, nil
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return "", nil, err
+	}
+
+	files := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".go" || (!includeTests && strings.HasSuffix(name, "_test.go")) {
+			continue
+		}
+		files = append(files, name)
+	}
+	sort.Strings(files)
+	return root, files, nil
+}
+
+func measureFunc(fset *token.FileSet, fn *ast.FuncDecl) funcMetrics {
+	name := fn.Name.Name
+	if fn.Recv != nil && len(fn.Recv.List) > 0 {
+		name = "(" + gotypes.ExprString(fn.Recv.List[0].Type) + ")." + name
+	}
+
+	start := fset.Position(fn.Pos()).Line
+	end := fset.Position(fn.End()).Line
+
+	return funcMetrics{
+		Name:       types.StringValue(name),
+		Line:       types.Int64Value(int64(start)),
+		Lines:      types.Int64Value(int64(end - start + 1)),
+		Params:     types.Int64Value(int64(countFields(fn.Type.Params))),
+		Results:    types.Int64Value(int64(countFields(fn.Type.Results))),
+		Complexity: types.Int64Value(int64(complexity(fn.Body))),
+		Depth:      types.Int64Value(int64(nestingDepth(fn.Body, 0))),
+	}
+}
+
+func countFields(fields *ast.FieldList) int {
+	if fields == nil {
+		return 0
+	}
+	n := 0
+	for _, field := range fields.List {
+		if len(field.Names) == 0 {
+			n++
+			continue
+		}
+		n += len(field.Names)
+	}
+	return n
+}
+
+// complexity computes McCabe's cyclomatic complexity in the same way as
+// gocyclo: one, plus one for each branch point and short-circuiting operator.
+func complexity(body *ast.BlockStmt) int {
+	c := 1
+	if body == nil {
+		return c
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			c++
+		case *ast.CaseClause:
+			if n.List != nil {
+				c++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				c++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				c++
+			}
+		}
+		return true
+	})
+	return c
+}
+
+// nestingDepth returns the deepest nesting of control-flow statements within
+// node, where depth is the nesting of node itself.
+func nestingDepth(node ast.Node, depth int) int {
+	deepest := depth
+	if node == nil {
+		return deepest
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n == node {
+			return true
+		}
+
+		switch n := n.(type) {
+		case *ast.IfStmt:
+			deepest = maxInt(deepest, nestingDepth(n.Body, depth+1))
+			// An "else if" continues the chain at the same depth.
+			if elseIf, ok := n.Else.(*ast.IfStmt); ok {
+				deepest = maxInt(deepest, nestingDepth(&ast.BlockStmt{List: []ast.Stmt{elseIf}}, depth))
+			} else if n.Else != nil {
+				deepest = maxInt(deepest, nestingDepth(n.Else, depth+1))
+			}
+			return false
+		case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+			deepest = maxInt(deepest, nestingDepth(n, depth+1))
+			return false
+		case *ast.FuncLit:
+			deepest = maxInt(deepest, nestingDepth(n.Body, depth+1))
+			return false
+		}
+		return true
+	})
+	return deepest
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,211 @@
+package datasources
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/mod/modfile"
+)
+
+var (
+	_ datasource.DataSource              = &goModuleDataSource{}
+	_ datasource.DataSourceWithConfigure = &goModuleDataSource{}
+)
+
+func NewGoModuleDataSource() datasource.DataSource {
+	return &goModuleDataSource{}
+}
+
+type goModuleDataSourceModel struct {
+	Directory  types.String    `tfsdk:"directory"`
+	ModulePath types.String    `tfsdk:"module_path"`
+	GoVersion  types.String    `tfsdk:"go_version"`
+	Toolchain  types.String    `tfsdk:"toolchain"`
+	Requires   []moduleRequire `tfsdk:"requires"`
+	Replaces   []moduleReplace `tfsdk:"replaces"`
+}
+
+type moduleRequire struct {
+	Path     types.String `tfsdk:"path"`
+	Version  types.String `tfsdk:"version"`
+	Indirect types.Bool   `tfsdk:"indirect"`
+}
+
+type moduleReplace struct {
+	OldPath    types.String `tfsdk:"old_path"`
+	OldVersion types.String `tfsdk:"old_version"`
+	NewPath    types.String `tfsdk:"new_path"`
+	NewVersion types.String `tfsdk:"new_version"`
+}
+
+type goModuleDataSource struct {
+	baseDir string
+}
+
+func (d *goModuleDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	dsd, ok := req.ProviderData.(*DataSourceData)
+	if !ok {
+		return
+	}
+	d.baseDir = dsd.BaseDir
+}
+
+func (d *goModuleDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_go_module"
+}
+
+func (d *goModuleDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads a go.mod file.",
+		Attributes: map[string]schema.Attribute{
+			"directory": schema.StringAttribute{
+				Optional:    true,
+				Description: "The directory holding go.mod, relative to the provider's base directory. Defaults to the base directory itself.",
+			},
+			"module_path": schema.StringAttribute{
+				Computed:    true,
+				Description: "The module path declared by the module directive.",
+			},
+			"go_version": schema.StringAttribute{
+				Computed:    true,
+				Description: "The Go language version declared by the go directive, or null if there isn't one.",
+			},
+			"toolchain": schema.StringAttribute{
+				Computed:    true,
+				Description: "The toolchain declared by the toolchain directive, or null if there isn't one.",
+			},
+			"requires": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The module's requirements, in file order.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"path": schema.StringAttribute{
+							Computed:    true,
+							Description: "The required module's path.",
+						},
+						"version": schema.StringAttribute{
+							Computed:    true,
+							Description: "The required module's version.",
+						},
+						"indirect": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the requirement is marked with an \"// indirect\" comment.",
+						},
+					},
+				},
+			},
+			"replaces": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The module's replace directives, in file order.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"old_path": schema.StringAttribute{
+							Computed:    true,
+							Description: "The path of the module being replaced.",
+						},
+						"old_version": schema.StringAttribute{
+							Computed:    true,
+							Description: "The version being replaced, or null if all versions are.",
+						},
+						"new_path": schema.StringAttribute{
+							Computed:    true,
+							Description: "The replacement module path or local directory.",
+						},
+						"new_version": schema.StringAttribute{
+							Computed:    true,
+							Description: "The replacement version, or null for local directories.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *goModuleDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state goModuleDataSourceModel
+
+	{
+		diags := req.Config.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+		if diags.HasError() {
+			return
+		}
+	}
+
+	path := filepath.Join(d.baseDir, state.Directory.ValueString(), "go.mod")
+
+	ctx = tflog.SetField(ctx, "path", path)
+	tflog.Debug(ctx, "Reading go.mod")
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read file from disk",
+			err.Error(),
+		)
+		return
+	}
+
+	f, err := modfile.Parse(path, contents, nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to parse go.mod",
+			err.Error(),
+		)
+		return
+	}
+
+	state.ModulePath = types.StringNull()
+	if f.Module != nil {
+		state.ModulePath = types.StringValue(f.Module.Mod.Path)
+	}
+
+	state.GoVersion = types.StringNull()
+	if f.Go != nil {
+		state.GoVersion = types.StringValue(f.Go.Version)
+	}
+
+	state.Toolchain = types.StringNull()
+	if f.Toolchain != nil {
+		state.Toolchain = types.StringValue(f.Toolchain.Name)
+	}
+
+	state.Requires = []moduleRequire{}
+	for _, req := range f.Require {
+		state.Requires = append(state.Requires, moduleRequire{
+			Path:     types.StringValue(req.Mod.Path),
+			Version:  types.StringValue(req.Mod.Version),
+			Indirect: types.BoolValue(req.Indirect),
+		})
+	}
+
+	state.Replaces = []moduleReplace{}
+	for _, rep := range f.Replace {
+		state.Replaces = append(state.Replaces, moduleReplace{
+			OldPath:    types.StringValue(rep.Old.Path),
+			OldVersion: stringOrNull(rep.Old.Version),
+			NewPath:    types.StringValue(rep.New.Path),
+			NewVersion: stringOrNull(rep.New.Version),
+		})
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func stringOrNull(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
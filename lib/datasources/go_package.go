@@ -0,0 +1,323 @@
+package datasources
+
+import (
+	"context"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	gotypes "go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &goPackageDataSource{}
+	_ datasource.DataSourceWithConfigure = &goPackageDataSource{}
+)
+
+func NewGoPackageDataSource() datasource.DataSource {
+	return &goPackageDataSource{}
+}
+
+type goPackageDataSourceModel struct {
+	Directory   types.String `tfsdk:"directory"`
+	PackageName types.String `tfsdk:"package_name"`
+	Doc         types.String `tfsdk:"doc"`
+	Funcs       []apiFunc    `tfsdk:"funcs"`
+	Types       []apiType    `tfsdk:"types"`
+	Consts      []apiValue   `tfsdk:"consts"`
+	Vars        []apiValue   `tfsdk:"vars"`
+	API         []string     `tfsdk:"api"`
+}
+
+type apiFunc struct {
+	Name      types.String `tfsdk:"name"`
+	Signature types.String `tfsdk:"signature"`
+	Doc       types.String `tfsdk:"doc"`
+}
+
+type apiType struct {
+	Name    types.String `tfsdk:"name"`
+	Kind    types.String `tfsdk:"kind"`
+	Decl    types.String `tfsdk:"decl"`
+	Doc     types.String `tfsdk:"doc"`
+	Methods []apiFunc    `tfsdk:"methods"`
+}
+
+type apiValue struct {
+	Name types.String `tfsdk:"name"`
+	Doc  types.String `tfsdk:"doc"`
+}
+
+type goPackageDataSource struct {
+	baseDir string
+}
+
+func (d *goPackageDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	dsd, ok := req.ProviderData.(*DataSourceData)
+	if !ok {
+		return
+	}
+	d.baseDir = dsd.BaseDir
+}
+
+func (d *goPackageDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_go_package"
+}
+
+func (d *goPackageDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	apiFuncAttributes := map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Computed:    true,
+			Description: "The function's name.",
+		},
+		"signature": schema.StringAttribute{
+			Computed:    true,
+			Description: "The function's declaration without its body.",
+		},
+		"doc": schema.StringAttribute{
+			Computed:    true,
+			Description: "The function's doc comment.",
+		},
+	}
+	apiValueAttributes := map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Computed:    true,
+			Description: "The declared name.",
+		},
+		"doc": schema.StringAttribute{
+			Computed:    true,
+			Description: "The doc comment of the declaration group.",
+		},
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Reads the exported API of a package: its functions, types, constants, and variables.",
+		Attributes: map[string]schema.Attribute{
+			"directory": schema.StringAttribute{
+				Required:    true,
+				Description: "The package's directory, relative to the provider's base directory.",
+			},
+			"package_name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The package to inspect, if the directory holds more than one.",
+			},
+			"doc": schema.StringAttribute{
+				Computed:    true,
+				Description: "The package's doc comment.",
+			},
+			"funcs": schema.ListNestedAttribute{
+				Computed:     true,
+				Description:  "Exported functions, including constructors, sorted by name.",
+				NestedObject: schema.NestedAttributeObject{Attributes: apiFuncAttributes},
+			},
+			"types": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Exported types, sorted by name.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The type's name.",
+						},
+						"kind": schema.StringAttribute{
+							Computed:    true,
+							Description: "One of \"struct\", \"interface\", \"alias\", or \"other\".",
+						},
+						"decl": schema.StringAttribute{
+							Computed:    true,
+							Description: "The type's declaration, with unexported fields removed.",
+						},
+						"doc": schema.StringAttribute{
+							Computed:    true,
+							Description: "The type's doc comment.",
+						},
+						"methods": schema.ListNestedAttribute{
+							Computed:     true,
+							Description:  "Exported methods with this type as their receiver, sorted by name.",
+							NestedObject: schema.NestedAttributeObject{Attributes: apiFuncAttributes},
+						},
+					},
+				},
+			},
+			"consts": schema.ListNestedAttribute{
+				Computed:     true,
+				Description:  "Exported constants.",
+				NestedObject: schema.NestedAttributeObject{Attributes: apiValueAttributes},
+			},
+			"vars": schema.ListNestedAttribute{
+				Computed:     true,
+				Description:  "Exported variables.",
+				NestedObject: schema.NestedAttributeObject{Attributes: apiValueAttributes},
+			},
+			"api": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "A sorted, one-line-per-identifier summary of the exported API, suitable for detecting changes.",
+			},
+		},
+	}
+}
+
+func (d *goPackageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state goPackageDataSourceModel
+
+	{
+		diags := req.Config.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+		if diags.HasError() {
+			return
+		}
+	}
+
+	dir := filepath.Join(d.baseDir, state.Directory.ValueString())
+
+	ctx = tflog.SetField(ctx, "directory", state.Directory.ValueString())
+	ctx = tflog.SetField(ctx, "path", dir)
+	tflog.Debug(ctx, "Reading package")
+
+	fset, files, err := parsePackage(dir, state.PackageName.ValueString(), parser.ParseComments)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to parse package",
+			err.Error(),
+		)
+		return
+	}
+
+	pkg, err := doc.NewFromFiles(fset, files, state.Directory.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read package documentation",
+			err.Error(),
+		)
+		return
+	}
+
+	describePackageAPI(fset, pkg, &state)
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// describePackageAPI fills model from a package's documentation, which only
+// includes exported identifiers.
+func describePackageAPI(fset *token.FileSet, pkg *doc.Package, model *goPackageDataSourceModel) {
+	model.PackageName = types.StringValue(pkg.Name)
+	model.Doc = types.StringValue(pkg.Doc)
+	model.Funcs = []apiFunc{}
+	model.Types = []apiType{}
+	model.Consts = []apiValue{}
+	model.Vars = []apiValue{}
+	model.API = []string{}
+
+	addFuncs := func(funcs []*doc.Func) {
+		for _, fn := range funcs {
+			f := describeAPIFunc(fset, fn)
+			model.Funcs = append(model.Funcs, f)
+			model.API = append(model.API, f.Signature.ValueString())
+		}
+	}
+	addValues := func(dst *[]apiValue, tok string, values []*doc.Value) {
+		for _, v := range values {
+			for _, spec := range v.Decl.Specs {
+				spec := spec.(*ast.ValueSpec)
+				for _, name := range spec.Names {
+					if !name.IsExported() {
+						continue
+					}
+					*dst = append(*dst, apiValue{Name: types.StringValue(name.Name), Doc: types.StringValue(v.Doc)})
+
+					line := tok + " " + name.Name
+					if spec.Type != nil {
+						line += " " + gotypes.ExprString(spec.Type)
+					}
+					model.API = append(model.API, line)
+				}
+			}
+		}
+	}
+
+	addFuncs(pkg.Funcs)
+	addValues(&model.Consts, "const", pkg.Consts)
+	addValues(&model.Vars, "var", pkg.Vars)
+
+	for _, t := range pkg.Types {
+		spec := typeSpec(t)
+		typ := apiType{
+			Name:    types.StringValue(t.Name),
+			Kind:    types.StringValue(typeKind(spec)),
+			Decl:    types.StringValue(printNode(fset, t.Decl)),
+			Doc:     types.StringValue(t.Doc),
+			Methods: []apiFunc{},
+		}
+
+		line := "type " + t.Name
+		if spec.Assign.IsValid() {
+			line += " ="
+		}
+		model.API = append(model.API, line+" "+gotypes.ExprString(spec.Type))
+
+		for _, m := range t.Methods {
+			f := describeAPIFunc(fset, m)
+			typ.Methods = append(typ.Methods, f)
+			model.API = append(model.API, f.Signature.ValueString())
+		}
+		model.Types = append(model.Types, typ)
+
+		// Constructors and typed values are grouped with their types by go/doc,
+		// but they're still top-level identifiers.
+		addFuncs(t.Funcs)
+		addValues(&model.Consts, "const", t.Consts)
+		addValues(&model.Vars, "var", t.Vars)
+	}
+
+	sort.Slice(model.Funcs, func(i, j int) bool {
+		return model.Funcs[i].Name.ValueString() < model.Funcs[j].Name.ValueString()
+	})
+	sort.Strings(model.API)
+}
+
+func describeAPIFunc(fset *token.FileSet, fn *doc.Func) apiFunc {
+	decl := *fn.Decl
+	decl.Doc = nil
+	decl.Body = nil
+
+	return apiFunc{
+		Name:      types.StringValue(fn.Name),
+		Signature: types.StringValue(printNode(fset, &decl)),
+		Doc:       types.StringValue(fn.Doc),
+	}
+}
+
+// typeSpec finds the spec declaring t within its (possibly grouped)
+// declaration.
+func typeSpec(t *doc.Type) *ast.TypeSpec {
+	for _, spec := range t.Decl.Specs {
+		if spec := spec.(*ast.TypeSpec); spec.Name.Name == t.Name {
+			return spec
+		}
+	}
+	return t.Decl.Specs[0].(*ast.TypeSpec)
+}
+
+func printNode(fset *token.FileSet, node ast.Node) string {
+	out := new(strings.Builder)
+	if err := printer.Fprint(out, fset, node); err != nil {
+		return ""
+	}
+	return out.String()
+}
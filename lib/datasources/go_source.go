@@ -2,6 +2,8 @@ package datasources
 
 import (
 	"context"
+	"go/parser"
+	"go/token"
 	"os"
 	"path/filepath"
 
@@ -21,8 +23,14 @@ func NewGoSourceDataSource() datasource.DataSource {
 }
 
 type goSourceDataSourceModel struct {
-	Filename types.String `tfsdk:"filename"`
-	Contents types.String `tfsdk:"contents"`
+	Filename    types.String `tfsdk:"filename"`
+	Contents    types.String `tfsdk:"contents"`
+	PackageName types.String `tfsdk:"package_name"`
+	Imports     []astImport  `tfsdk:"imports"`
+	Funcs       []astFunc    `tfsdk:"funcs"`
+	Types       []astType    `tfsdk:"types"`
+	Consts      []astValue   `tfsdk:"consts"`
+	Vars        []astValue   `tfsdk:"vars"`
 }
 
 type goSourceDataSource struct {
@@ -46,18 +54,24 @@ func (d *goSourceDataSource) Metadata(_ context.Context, req datasource.Metadata
 }
 
 func (d *goSourceDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
-	resp.Schema = schema.Schema{
-		Attributes: map[string]schema.Attribute{
-			"filename": schema.StringAttribute{
-				Required:    true,
-				Description: "The absolute path to the file on-disk.",
-			},
-			"contents": schema.StringAttribute{
-				Computed:    true,
-				Description: "The rendered content as it exists on-disk.",
-			},
+	attributes := map[string]schema.Attribute{
+		"filename": schema.StringAttribute{
+			Required:    true,
+			Description: "The file to read, relative to the provider's base directory.",
+		},
+		"contents": schema.StringAttribute{
+			Computed:    true,
+			Description: "The rendered content as it exists on-disk.",
 		},
 	}
+	for name, attr := range astAttributes {
+		attributes[name] = attr
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Reads a Go source file and describes its declarations.",
+		Attributes:  attributes,
+	}
 }
 
 func (d *goSourceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -88,6 +102,19 @@ func (d *goSourceDataSource) Read(ctx context.Context, req datasource.ReadReques
 
 	state.Contents = types.StringValue(string(contents))
 
+	// The structural attributes are best-effort, so files that aren't valid Go
+	// can still be read.
+	f, err := parser.ParseFile(token.NewFileSet(), path, contents, parser.SkipObjectResolution)
+	if err != nil {
+		resp.Diagnostics.AddWarning(
+			"Unable to parse Go source",
+			"The file's contents were read, but its structure is unavailable: "+err.Error(),
+		)
+		state.PackageName = types.StringNull()
+	} else {
+		describeFile(f, &state)
+	}
+
 	diags := resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 }
@@ -0,0 +1,60 @@
+package datasources
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// parsePackage parses the non-test Go files in dir. If the directory holds
+// more than one package, name selects between them.
+func parsePackage(dir string, name string, mode parser.Mode) (*token.FileSet, []*ast.File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fset := token.NewFileSet()
+	pkgs := map[string][]*ast.File{}
+	for _, entry := range entries {
+		filename := entry.Name()
+		if entry.IsDir() || filepath.Ext(filename) != ".go" || strings.HasSuffix(filename, "_test.go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, filepath.Join(dir, filename), nil, mode)
+		if err != nil {
+			return nil, nil, err
+		}
+		pkgs[f.Name.Name] = append(pkgs[f.Name.Name], f)
+	}
+
+	if name != "" {
+		files, ok := pkgs[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("no files in %s declare package %s", dir, name)
+		}
+		return fset, files, nil
+	}
+
+	switch len(pkgs) {
+	case 0:
+		return nil, nil, fmt.Errorf("no Go files in %s", dir)
+	case 1:
+		for _, files := range pkgs {
+			return fset, files, nil
+		}
+	}
+
+	names := []string{}
+	for name := range pkgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return nil, nil, fmt.Errorf("found multiple packages in %s (%s); set package_name to choose one", dir, strings.Join(names, ", "))
+}
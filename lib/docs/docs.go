@@ -0,0 +1,319 @@
+// Package docs generates the provider's documentation in the layout read by
+// the Terraform Registry, and produced by tfplugindocs: an index.md page for
+// the provider, and a page under resources/ or data-sources/ for each
+// resource and data source. Pages are built from the schemas the provider
+// serves, so every attribute and block must have a description, and include
+// the examples found in the matching tfplugindocs examples directory:
+//
+//	examples/provider/provider.tf
+//	examples/resources/<type>/resource.tf
+//	examples/data-sources/<type>/data-source.tf
+//
+// Alongside a resource's example, files named <file>.golden hold what the
+// example renders, and are shown after it.
+package docs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Generate returns the documentation for p, keyed by slash-separated path
+// relative to the docs directory.
+func Generate(ctx context.Context, p provider.Provider, examplesDir string) (map[string][]byte, error) {
+	var meta provider.MetadataResponse
+	p.Metadata(ctx, provider.MetadataRequest{}, &meta)
+
+	resp, err := providerserver.NewProtocol6(p)().GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			return nil, fmt.Errorf("%s: %s", d.Summary, d.Detail)
+		}
+	}
+
+	g := &generator{examplesDir: examplesDir}
+	pages := map[string][]byte{}
+
+	pages["index.md"] = g.page(page{
+		title:   meta.TypeName + " Provider",
+		heading: meta.TypeName + " Provider",
+		address: "provider",
+		schema:  resp.Provider,
+		example: filepath.Join("provider", "provider.tf"),
+	})
+
+	for name, schema := range resp.ResourceSchemas {
+		pages["resources/"+strings.TrimPrefix(name, meta.TypeName+"_")+".md"] = g.page(page{
+			title:   name + " Resource - " + meta.TypeName,
+			heading: name + " (Resource)",
+			address: name,
+			schema:  schema,
+			example: filepath.Join("resources", name, "resource.tf"),
+		})
+	}
+
+	for name, schema := range resp.DataSourceSchemas {
+		pages["data-sources/"+strings.TrimPrefix(name, meta.TypeName+"_")+".md"] = g.page(page{
+			title:   name + " Data Source - " + meta.TypeName,
+			heading: name + " (Data Source)",
+			address: "data." + name,
+			schema:  schema,
+			example: filepath.Join("data-sources", name, "data-source.tf"),
+		})
+	}
+
+	if len(g.undocumented) > 0 {
+		sort.Strings(g.undocumented)
+		return nil, fmt.Errorf("missing descriptions for %s", strings.Join(g.undocumented, ", "))
+	}
+	if g.err != nil {
+		return nil, g.err
+	}
+	return pages, nil
+}
+
+type page struct {
+	title   string
+	heading string
+	// address prefixes the paths of undocumented attributes and blocks.
+	address string
+	schema  *tfprotov6.Schema
+	// example is relative to the examples directory.
+	example string
+}
+
+type generator struct {
+	examplesDir  string
+	undocumented []string
+	err          error
+}
+
+func (g *generator) page(p page) []byte {
+	w := new(strings.Builder)
+	block := p.schema.Block
+
+	description := block.Description
+	if description == "" {
+		g.undocumented = append(g.undocumented, p.address)
+	}
+
+	fmt.Fprintf(w, "---\npage_title: %q\nsubcategory: \"\"\ndescription: |-\n  %s\n---\n\n", p.title, description)
+	fmt.Fprintf(w, "# %s\n\n%s\n", p.heading, description)
+
+	g.writeExample(w, p.example)
+
+	w.WriteString("\n## Schema\n")
+	nested := g.writeBlock(w, p.address, "", block, "###")
+	// Document nested schemas depth first, so each is followed by those
+	// nested within it.
+	for len(nested) > 0 {
+		n := nested[0]
+		fmt.Fprintf(w, "\n<a id=%q></a>\n### Nested Schema for `%s`\n", n.anchor, n.path)
+		nested = append(g.writeBlock(w, p.address, n.path, n.block, ""), nested[1:]...)
+	}
+
+	return []byte(w.String())
+}
+
+// writeExample writes the example at name, if there is one, followed by any
+// golden files next to it.
+func (g *generator) writeExample(w *strings.Builder, name string) {
+	filename := filepath.Join(g.examplesDir, name)
+	example, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		g.err = err
+		return
+	}
+	fmt.Fprintf(w, "\n## Example Usage\n\n```terraform\n%s```\n", example)
+
+	goldens, err := filepath.Glob(filepath.Join(filepath.Dir(filename), "*.golden"))
+	if err != nil {
+		g.err = err
+		return
+	}
+	sort.Strings(goldens)
+	for _, golden := range goldens {
+		contents, err := os.ReadFile(golden)
+		if err != nil {
+			g.err = err
+			return
+		}
+		name := strings.TrimSuffix(filepath.Base(golden), ".golden")
+		lang := "go"
+		if filepath.Ext(name) != ".go" {
+			// Such as go.mod, which isn't Go source.
+			lang = "text"
+		}
+		fmt.Fprintf(w, "\nThis renders `%s`:\n\n```%s\n%s```\n", name, lang, contents)
+	}
+}
+
+// nestedSchema is a block or nested attribute documented in its own section
+// after the ones referring to it.
+type nestedSchema struct {
+	path   string
+	anchor string
+	block  *tfprotov6.SchemaBlock
+}
+
+// entry is a single attribute or block in a schema's list of them.
+type entry struct {
+	name        string
+	kind        string
+	description string
+	nested      *nestedSchema
+}
+
+// writeBlock writes the attributes and blocks of block, grouped as required,
+// optional, and read-only, and returns the nested schemas they refer to. A
+// heading prefix of "" writes the groups as plain labels, as for nested
+// schemas.
+func (g *generator) writeBlock(w *strings.Builder, address string, prefix string, block *tfprotov6.SchemaBlock, heading string) []nestedSchema {
+	var required, optional, readOnly []entry
+	nested := []nestedSchema{}
+
+	qualify := func(name string) string {
+		if prefix == "" {
+			return name
+		}
+		return prefix + "." + name
+	}
+	anchorFor := func(kind string, path string) string {
+		return "nested" + kind + "--" + strings.ReplaceAll(path, ".", "--")
+	}
+
+	for _, attr := range block.Attributes {
+		e := entry{name: attr.Name, kind: typeName(attr.Type), description: attr.Description}
+		if attr.Description == "" {
+			g.undocumented = append(g.undocumented, address+"."+qualify(attr.Name))
+		}
+
+		if attr.NestedType != nil {
+			e.kind = "Attributes" + nestingName(attr.NestedType.Nesting.String())
+			e.nested = &nestedSchema{
+				path:   qualify(attr.Name),
+				anchor: anchorFor("att", qualify(attr.Name)),
+				block:  &tfprotov6.SchemaBlock{Attributes: attr.NestedType.Attributes},
+			}
+			nested = append(nested, *e.nested)
+		}
+
+		switch {
+		case attr.Required:
+			required = append(required, e)
+		case attr.Optional:
+			optional = append(optional, e)
+		default:
+			readOnly = append(readOnly, e)
+		}
+	}
+
+	for _, b := range block.BlockTypes {
+		n := nestedSchema{
+			path:   qualify(b.TypeName),
+			anchor: anchorFor("block", qualify(b.TypeName)),
+			block:  b.Block,
+		}
+		if b.Block.Description == "" {
+			g.undocumented = append(g.undocumented, address+"."+n.path)
+		}
+		nested = append(nested, n)
+
+		e := entry{
+			name:        b.TypeName,
+			kind:        "Block" + nestingName(b.Nesting.String()),
+			description: b.Block.Description,
+			nested:      &n,
+		}
+		if b.MinItems > 0 {
+			required = append(required, e)
+		} else {
+			optional = append(optional, e)
+		}
+	}
+
+	for _, group := range []struct {
+		label   string
+		entries []entry
+	}{
+		{"Required", required},
+		{"Optional", optional},
+		{"Read-Only", readOnly},
+	} {
+		if len(group.entries) == 0 {
+			continue
+		}
+		if heading == "" {
+			fmt.Fprintf(w, "\n%s:\n\n", group.label)
+		} else {
+			fmt.Fprintf(w, "\n%s %s\n\n", heading, group.label)
+		}
+
+		sort.Slice(group.entries, func(i, j int) bool { return group.entries[i].name < group.entries[j].name })
+		for _, e := range group.entries {
+			fmt.Fprintf(w, "- `%s` (%s) %s", e.name, e.kind, e.description)
+			if e.nested != nil {
+				fmt.Fprintf(w, " (see [below for nested schema](#%s))", e.nested.anchor)
+			}
+			w.WriteString("\n")
+		}
+	}
+
+	sort.SliceStable(nested, func(i, j int) bool { return nested[i].path < nested[j].path })
+	return nested
+}
+
+// nestingName describes a nesting mode as a suffix for "Block" or
+// "Attributes", e.g. " List". Single nesting has no suffix.
+func nestingName(mode string) string {
+	switch mode {
+	case "LIST":
+		return " List"
+	case "SET":
+		return " Set"
+	case "MAP":
+		return " Map"
+	}
+	return ""
+}
+
+func typeName(t tftypes.Type) string {
+	switch t := t.(type) {
+	case nil:
+		return ""
+	case tftypes.List:
+		return "List of " + typeName(t.ElementType)
+	case tftypes.Set:
+		return "Set of " + typeName(t.ElementType)
+	case tftypes.Map:
+		return "Map of " + typeName(t.ElementType)
+	case tftypes.Object:
+		return "Object"
+	}
+
+	switch {
+	case t.Is(tftypes.String):
+		return "String"
+	case t.Is(tftypes.Bool):
+		return "Boolean"
+	case t.Is(tftypes.Number):
+		return "Number"
+	}
+	return t.String()
+}
@@ -0,0 +1,46 @@
+package docs
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"terraform-provider-caiac/lib"
+)
+
+// TestDocsAreGenerated checks that docs holds exactly the generated pages:
+// none out of date or missing, and none left over from a resource or data
+// source that no longer exists.
+func TestDocsAreGenerated(t *testing.T) {
+	pages, err := Generate(context.Background(), caiac.New(), filepath.Join("..", "..", "examples"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join("..", "..", "docs")
+	for name, want := range pages {
+		got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil || string(got) != string(want) {
+			t.Errorf("docs/%s is out of date; regenerate it with go generate", name)
+		}
+	}
+
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		name, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if _, ok := pages[filepath.ToSlash(name)]; !ok {
+			t.Errorf("docs/%s isn't generated by any resource or data source; remove it", filepath.ToSlash(name))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
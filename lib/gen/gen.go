@@ -0,0 +1,139 @@
+// Package gen converts a model of a Go source file to its AST and source
+// code. It's the converter behind the caiac_go_source resource, usable
+// without Terraform:
+//
+//	src, err := gen.Render(ctx, &gen.File{
+//		Package: "main",
+//		Imports: []gen.Import{{Path: "fmt"}},
+//		Funcs:   []gen.Func{...},
+//	})
+//
+// The tfsdk struct tags let the provider decode Terraform configuration
+// directly into these types, and the json tags define the format read by
+// ParseJSON.
+package gen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"strings"
+)
+
+// File is a single Go source file.
+type File struct {
+	Package string   `json:"package_name,omitempty"`
+	Imports []Import `json:"import,omitempty"`
+	Funcs   []Func   `json:"func,omitempty"`
+
+	// PruneUnusedImports drops imports that aren't referenced by any
+	// function.
+	PruneUnusedImports bool `json:"prune_unused_imports,omitempty"`
+	// AddMissingImports adds standard library imports for packages that are
+	// referenced but not imported.
+	AddMissingImports bool `json:"add_missing_imports,omitempty"`
+	// LocalImportPrefix is the import path prefix of the local module.
+	// Matching imports are grouped after third-party imports.
+	LocalImportPrefix string `json:"local_import_prefix,omitempty"`
+}
+
+// ParseJSON decodes a File from JSON. The document mirrors the HCL accepted
+// by the caiac_go_source resource: each block becomes an object, and each
+// list of blocks an array, under the same names. For example:
+//
+//	{
+//	  "package_name": "main",
+//	  "import": [{"path": "fmt"}],
+//	  "func": [{
+//	    "name": "main",
+//	    "body": {"statement": [{
+//	      "kind": "expression",
+//	      "expression": {"kind": "call", "call": {
+//	        "func": {"from": "fmt", "prop": "Println"},
+//	        "arg": [{"kind": "string", "value": "Hello, world"}]
+//	      }}
+//	    }]}
+//	  }]
+//	}
+//
+// Unknown fields are an error, so that typos don't silently drop code.
+func ParseJSON(data []byte) (*File, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var f File
+	if err := dec.Decode(&f); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("unexpected data after the top-level object")
+	}
+	return &f, nil
+}
+
+// ToAst converts f to an AST. Positions in the returned file set only
+// determine how imports are grouped when printed. If the model is invalid,
+// the error is Problems describing every invalid part of it.
+func (f *File) ToAst(ctx context.Context) (*token.FileSet, *ast.File, error) {
+	var problems Problems
+
+	if !token.IsIdentifier(f.Package) {
+		problems.add(Root("package_name"), "Invalid package name", "Package names must be valid Go identifiers.")
+	}
+
+	// Convert every declaration before bailing out, so that all problems in
+	// the model are reported at once.
+	specs := []*ast.ImportSpec{}
+	for i, imp := range f.Imports {
+		spec, d := imp.toAst(ctx, Root("import").AtListIndex(i))
+		problems = append(problems, d...)
+		specs = append(specs, spec)
+	}
+
+	funcs := []ast.Decl{}
+	for i, fn := range f.Funcs {
+		decl, d := fn.toAst(ctx, Root("func").AtListIndex(i))
+		problems = append(problems, d...)
+		funcs = append(funcs, decl)
+	}
+
+	if len(problems) > 0 {
+		return nil, nil, problems
+	}
+
+	fset := token.NewFileSet()
+	groups := organizeImports(ctx, specs, funcs, importOptions{
+		PruneUnused: f.PruneUnusedImports,
+		AddMissing:  f.AddMissingImports,
+		LocalPrefix: f.LocalImportPrefix,
+	})
+
+	decls := []ast.Decl{}
+	if importDecl := makeImportDecl(fset, groups); importDecl != nil {
+		decls = append(decls, importDecl)
+	}
+	decls = append(decls, funcs...)
+
+	return fset, &ast.File{
+		Name:  ast.NewIdent(f.Package),
+		Decls: decls,
+	}, nil
+}
+
+// Render converts f to gofmt-ed Go source code.
+func Render(ctx context.Context, f *File) ([]byte, error) {
+	fset, file, err := f.ToAst(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	contents := new(strings.Builder)
+	if err := format.Node(contents, fset, file); err != nil {
+		return nil, err
+	}
+	return []byte(contents.String()), nil
+}
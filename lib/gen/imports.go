@@ -0,0 +1,387 @@
+package gen
+
+import (
+	"bytes"
+	"context"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// importOptions controls the goimports-like handling of a file's imports.
+type importOptions struct {
+	// PruneUnused drops imports that aren't referenced by any declaration.
+	PruneUnused bool
+	// AddMissing adds standard library imports for selectors like fmt.Println
+	// whose package isn't otherwise imported.
+	AddMissing bool
+	// LocalPrefix identifies imports belonging to the local module, which are
+	// grouped after third-party imports.
+	LocalPrefix string
+}
+
+type importGroup int
+
+const (
+	groupStdlib importGroup = iota
+	groupThirdParty
+	groupLocal
+)
+
+// organizeImports deduplicates, prunes and completes a file's imports, then
+// returns them sorted and split into stdlib, third-party, and local groups.
+func organizeImports(ctx context.Context, specs []*ast.ImportSpec, decls []ast.Decl, opts importOptions) [][]*ast.ImportSpec {
+	specs = dedupeImports(specs)
+	refs, locals := referencedPackages(decls)
+
+	if opts.PruneUnused {
+		kept := []*ast.ImportSpec{}
+		for _, spec := range specs {
+			name := importLocalName(spec)
+			if name == "_" || name == "." || refs[name] {
+				kept = append(kept, spec)
+				continue
+			}
+			trace(ctx, "Pruning unused import", map[string]interface{}{
+				"import": importPath(spec),
+			})
+		}
+		specs = kept
+	}
+
+	if opts.AddMissing {
+		declared := map[string]bool{}
+		for _, spec := range specs {
+			declared[importLocalName(spec)] = true
+		}
+
+		missing := []string{}
+		for name := range refs {
+			if declared[name] || locals[name] {
+				continue
+			}
+			if _, ok := stdlibPackages[name]; ok {
+				missing = append(missing, name)
+			}
+		}
+		sort.Strings(missing)
+
+		for _, name := range missing {
+			trace(ctx, "Adding missing import", map[string]interface{}{
+				"import": stdlibPackages[name],
+			})
+			specs = append(specs, &ast.ImportSpec{
+				Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(stdlibPackages[name])},
+			})
+		}
+	}
+
+	groups := make([][]*ast.ImportSpec, groupLocal+1)
+	for _, spec := range specs {
+		g := classifyImport(importPath(spec), opts.LocalPrefix)
+		groups[g] = append(groups[g], spec)
+	}
+
+	res := [][]*ast.ImportSpec{}
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			pi, pj := importPath(group[i]), importPath(group[j])
+			if pi != pj {
+				return pi < pj
+			}
+			return importLocalName(group[i]) < importLocalName(group[j])
+		})
+		res = append(res, group)
+	}
+
+	return res
+}
+
+// makeImportDecl builds a single import declaration from groups of specs,
+// assigning synthetic positions within fset so that the printer separates
+// each group with a blank line. It returns nil if there are no imports.
+func makeImportDecl(fset *token.FileSet, groups [][]*ast.ImportSpec) ast.Decl {
+	count := 0
+	for _, group := range groups {
+		count += len(group)
+	}
+	if count == 0 {
+		return nil
+	}
+
+	// One line for "import (", one per spec, one between each group, and one
+	// for the closing paren.
+	lines := make([]int, 1+count+len(groups)+1)
+	for i := range lines {
+		lines[i] = i
+	}
+	file := fset.AddFile("", -1, len(lines))
+	file.SetLines(lines)
+
+	line := 1
+	decl := &ast.GenDecl{Tok: token.IMPORT, TokPos: file.LineStart(line)}
+	if count > 1 {
+		decl.Lparen = file.LineStart(line)
+	}
+
+	for i, group := range groups {
+		if i > 0 {
+			line++
+		}
+		for _, spec := range group {
+			line++
+			spec.Path.ValuePos = file.LineStart(line)
+			if spec.Name != nil {
+				spec.Name.NamePos = spec.Path.ValuePos
+			}
+			decl.Specs = append(decl.Specs, spec)
+		}
+	}
+
+	if count > 1 {
+		decl.Rparen = file.LineStart(line + 1)
+	}
+
+	return decl
+}
+
+func dedupeImports(specs []*ast.ImportSpec) []*ast.ImportSpec {
+	seen := map[string]bool{}
+	res := []*ast.ImportSpec{}
+	for _, spec := range specs {
+		key := importPath(spec)
+		if spec.Name != nil {
+			key = spec.Name.Name + " " + key
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		res = append(res, spec)
+	}
+	return res
+}
+
+// referencedPackages returns the identifiers used as the left-hand side of a
+// selector, along with names declared locally (functions and parameters) that
+// could shadow a package name.
+func referencedPackages(decls []ast.Decl) (map[string]bool, map[string]bool) {
+	refs := map[string]bool{}
+	locals := map[string]bool{}
+
+	for _, decl := range decls {
+		ast.Inspect(decl, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncDecl:
+				locals[node.Name.Name] = true
+			case *ast.Field:
+				for _, name := range node.Names {
+					locals[name.Name] = true
+				}
+				// Types are Go source held in a single identifier, e.g.
+				// "*testing.T", so parse them to find their packages.
+				if typ, ok := node.Type.(*ast.Ident); ok {
+					for pkg := range typePackages(typ.Name) {
+						refs[pkg] = true
+					}
+				}
+			case *ast.SelectorExpr:
+				if x, ok := node.X.(*ast.Ident); ok {
+					refs[x.Name] = true
+				}
+			case *ast.BasicLit:
+				// Identifier literals can smuggle in qualified names like os.Args.
+				if node.Kind == token.IDENT {
+					if pkg, _, ok := strings.Cut(node.Value, "."); ok {
+						refs[pkg] = true
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	return refs, locals
+}
+
+// typePackages returns the package names qualifying identifiers in a type
+// written as Go source, e.g. "io" and "http" in "map[io.Reader]http.Handler".
+func typePackages(typ string) map[string]bool {
+	pkgs := map[string]bool{}
+	expr, err := parser.ParseExpr(typ)
+	if err != nil {
+		return pkgs
+	}
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if x, ok := sel.X.(*ast.Ident); ok {
+				pkgs[x.Name] = true
+			}
+		}
+		return true
+	})
+	return pkgs
+}
+
+func importPath(spec *ast.ImportSpec) string {
+	p, err := strconv.Unquote(spec.Path.Value)
+	if err != nil {
+		return spec.Path.Value
+	}
+	return p
+}
+
+// importLocalName returns the name a package is referred to by in the file,
+// using the same assumptions as goimports for unnamed imports.
+func importLocalName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+
+	p := importPath(spec)
+	base := path.Base(p)
+	if strings.HasPrefix(base, "v") {
+		if _, err := strconv.Atoi(base[1:]); err == nil && base != p {
+			base = path.Base(path.Dir(p))
+		}
+	}
+	base = strings.TrimPrefix(base, "go-")
+	if i := strings.IndexAny(base, ".-"); i >= 0 {
+		base = base[:i]
+	}
+	return base
+}
+
+func classifyImport(p string, localPrefix string) importGroup {
+	if localPrefix != "" && (p == localPrefix || strings.HasPrefix(p, strings.TrimSuffix(localPrefix, "/")+"/")) {
+		return groupLocal
+	}
+
+	first, _, _ := strings.Cut(p, "/")
+	if strings.Contains(first, ".") {
+		return groupThirdParty
+	}
+	return groupStdlib
+}
+
+// stdlibPackages maps package names to the standard library import paths
+// that AddMissing will add for them. Where several packages share a name,
+// the most commonly used one wins. Only packages available in the Go version
+// declared by go.mod are listed.
+var stdlibPackages = map[string]string{
+	"ast":       "go/ast",
+	"atomic":    "sync/atomic",
+	"base64":    "encoding/base64",
+	"big":       "math/big",
+	"binary":    "encoding/binary",
+	"bufio":     "bufio",
+	"bytes":     "bytes",
+	"context":   "context",
+	"csv":       "encoding/csv",
+	"embed":     "embed",
+	"errors":    "errors",
+	"exec":      "os/exec",
+	"filepath":  "path/filepath",
+	"flag":      "flag",
+	"fmt":       "fmt",
+	"format":    "go/format",
+	"fs":        "io/fs",
+	"gzip":      "compress/gzip",
+	"heap":      "container/heap",
+	"hex":       "encoding/hex",
+	"http":      "net/http",
+	"httptest":  "net/http/httptest",
+	"io":        "io",
+	"ioutil":    "io/ioutil",
+	"json":      "encoding/json",
+	"list":      "container/list",
+	"log":       "log",
+	"math":      "math",
+	"net":       "net",
+	"os":        "os",
+	"parser":    "go/parser",
+	"path":      "path",
+	"rand":      "math/rand",
+	"reflect":   "reflect",
+	"regexp":    "regexp",
+	"runtime":   "runtime",
+	"sha256":    "crypto/sha256",
+	"signal":    "os/signal",
+	"sort":      "sort",
+	"sql":       "database/sql",
+	"strconv":   "strconv",
+	"strings":   "strings",
+	"sync":      "sync",
+	"syscall":   "syscall",
+	"tabwriter": "text/tabwriter",
+	"template":  "text/template",
+	"testing":   "testing",
+	"time":      "time",
+	"tls":       "crypto/tls",
+	"token":     "go/token",
+	"unicode":   "unicode",
+	"unsafe":    "unsafe",
+	"url":       "net/url",
+	"utf8":      "unicode/utf8",
+	"xml":       "encoding/xml",
+}
+
+// FixImports applies f's import handling to src, a complete Go source file
+// that may hold code built outside the model, such as code generated as
+// text. Its import declarations are replaced with a single one, organized as
+// if by ToAst, and the rest of the file is kept as is.
+func (f *File) FixImports(ctx context.Context, src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := []*ast.ImportSpec{}
+	decls := []ast.Decl{}
+	rest := new(bytes.Buffer)
+	offset := fset.Position(file.Name.End()).Offset
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			decls = append(decls, decl)
+			continue
+		}
+		for _, spec := range gen.Specs {
+			spec := spec.(*ast.ImportSpec)
+			specs = append(specs, &ast.ImportSpec{Name: spec.Name, Path: &ast.BasicLit{Kind: token.STRING, Value: spec.Path.Value}})
+		}
+		rest.Write(src[offset:fset.Position(gen.Pos()).Offset])
+		offset = fset.Position(gen.End()).Offset
+	}
+	rest.Write(src[offset:])
+
+	groups := organizeImports(ctx, specs, decls, importOptions{
+		PruneUnused: f.PruneUnusedImports,
+		AddMissing:  f.AddMissingImports,
+		LocalPrefix: f.LocalImportPrefix,
+	})
+
+	imports := new(bytes.Buffer)
+	importFset := token.NewFileSet()
+	if decl := makeImportDecl(importFset, groups); decl != nil {
+		if err := format.Node(imports, importFset, decl); err != nil {
+			return nil, err
+		}
+	}
+
+	out := new(bytes.Buffer)
+	out.Write(src[:fset.Position(file.Name.End()).Offset])
+	out.WriteString("\n\n")
+	out.Write(imports.Bytes())
+	out.Write(rest.Bytes())
+	return format.Source(out.Bytes())
+}
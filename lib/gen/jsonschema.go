@@ -0,0 +1,126 @@
+package gen
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// schemaDescriptions documents each type in the generated JSON Schema.
+var schemaDescriptions = map[string]string{
+	"File":       "A Go source file, as accepted by caiac_go_source's definition_json attribute.",
+	"Import":     "An import declaration, optionally renaming the package.",
+	"Func":       "A top-level function declaration. Without a body, it declares a function implemented elsewhere.",
+	"Signature":  "A function's parameters and results.",
+	"Field":      "A function parameter or result. Both name and type are Go source.",
+	"Body":       "A function body.",
+	"Statement":  "A single statement.",
+	"Expression": "An expression, described by the property named by its kind.",
+	"Call":       "A function call, with either literal arguments or expression arguments.",
+	"Selector":   "A qualified name such as fmt.Println, or, without from, a local name.",
+	"Identifier": "A reference to a name in scope.",
+	"Literal":    "A literal value. String values are quoted and escaped when rendered; others are used as is.",
+}
+
+// schemaEnums lists the allowed values of kind properties.
+var schemaEnums = map[string][]string{
+	"Statement.kind":  {StmtExpr, StmtReturn},
+	"Expression.kind": {ExprCall, ExprSelector, ExprLiteral, ExprIdentifier},
+	"Literal.kind":    {LitIdent, LitString, LitInt},
+}
+
+// schemaRequired lists properties that must be present for a model to be
+// valid.
+var schemaRequired = map[string]bool{
+	"Import.path":     true,
+	"Func.name":       true,
+	"Statement.kind":  true,
+	"Expression.kind": true,
+	"Call.func":       true,
+	"Selector.prop":   true,
+	"Identifier.name": true,
+	"Literal.kind":    true,
+}
+
+// JSONSchema returns a JSON Schema (draft 2020-12) describing the documents
+// read by ParseJSON, generated from the model's types so the two can't drift
+// apart.
+func JSONSchema() []byte {
+	defs := map[string]interface{}{}
+	root := schemaFor(reflect.TypeOf(File{}), defs)
+
+	doc := map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title":   "CaIaC Go source definition",
+		"$ref":    root["$ref"],
+		"$defs":   defs,
+	}
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		// The document is built from maps, slices, and strings alone.
+		panic(err)
+	}
+	return append(out, '\n')
+}
+
+// schemaFor returns the schema for t, adding definitions for any structs it
+// refers to to defs.
+func schemaFor(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaFor(t.Elem(), defs)
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem(), defs)}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Struct:
+	default:
+		panic("gen: no JSON Schema for " + t.String())
+	}
+
+	name := t.Name()
+	ref := map[string]interface{}{"$ref": "#/$defs/" + name}
+	if _, ok := defs[name]; ok {
+		return ref
+	}
+
+	// Register the definition before filling it in, so that recursive types
+	// like Expression refer back to it rather than recursing forever.
+	properties := map[string]interface{}{}
+	def := map[string]interface{}{
+		"type":                 "object",
+		"description":          schemaDescriptions[name],
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	defs[name] = def
+
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		prop, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if prop == "" || prop == "-" {
+			continue
+		}
+
+		s := schemaFor(field.Type, defs)
+		if enum, ok := schemaEnums[name+"."+prop]; ok {
+			s["enum"] = enum
+		}
+		properties[prop] = s
+
+		if schemaRequired[name+"."+prop] {
+			required = append(required, prop)
+		}
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		def["required"] = required
+	}
+
+	return ref
+}
@@ -0,0 +1,21 @@
+package gen
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+// publishedSchema is the JSON Schema editors are pointed at.
+const publishedSchema = "../../schema/go_source.schema.json"
+
+func TestJSONSchemaIsPublished(t *testing.T) {
+	published, err := os.ReadFile(publishedSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(published, JSONSchema()) {
+		t.Errorf("%s is out of date; regenerate it with:\n\n\tgo run . schema > schema/go_source.schema.json", publishedSchema)
+	}
+}
@@ -0,0 +1,415 @@
+package gen
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"terraform-provider-caiac/lib/astutil"
+)
+
+// Import is an import declaration, optionally renaming the package.
+type Import struct {
+	Name *string `tfsdk:"name" json:"name,omitempty"`
+	Path string  `tfsdk:"path" json:"path,omitempty"`
+}
+
+func (i *Import) toAst(ctx context.Context, p Path) (*ast.ImportSpec, Problems) {
+	traceNode(ctx, p, "import")
+	var problems Problems
+
+	if i.Path == "" {
+		problems.add(p.AtName("path"), "Invalid import path", "Import paths must not be empty.")
+	}
+	problems = append(problems, validateIdent(p.AtName("name"), i.Name)...)
+
+	return &ast.ImportSpec{
+		Name: astutil.MaybeNewIdent(i.Name),
+		Path: astutil.NewStringLiteral(i.Path),
+	}, problems
+}
+
+// Field is a function parameter or result. Both name and type are Go source,
+// e.g. "w" and "io.Writer"; results are often unnamed.
+type Field struct {
+	Name *string `tfsdk:"name" json:"name,omitempty"`
+	Type *string `tfsdk:"type" json:"type,omitempty"`
+}
+
+func (f *Field) toAst(ctx context.Context, p Path) (*ast.Field, Problems) {
+	traceNode(ctx, p, "field")
+	var problems Problems
+
+	problems = append(problems, validateIdent(p.AtName("name"), f.Name)...)
+	if f.Type == nil {
+		problems.add(p.AtName("type"), "Missing field type", "Parameters and results must declare a type.")
+	}
+
+	// Unnamed fields have no names at all, so that a single unnamed result
+	// is printed without parentheses.
+	var names []*ast.Ident
+	if name := astutil.MaybeNewIdent(f.Name); name != nil {
+		names = []*ast.Ident{name}
+	}
+	return &ast.Field{
+		Names: names,
+		Type:  astutil.MaybeNewIdent(f.Type),
+	}, problems
+}
+
+// Signature is a function's parameters and results.
+type Signature struct {
+	Params  []Field `tfsdk:"param" json:"param,omitempty"`
+	Results []Field `tfsdk:"result" json:"result,omitempty"`
+}
+
+func (s *Signature) toAst(ctx context.Context, p Path) (*ast.FuncType, Problems) {
+	var problems Problems
+
+	// An omitted signature block is equivalent to an empty one.
+	if s == nil {
+		return &ast.FuncType{Params: &ast.FieldList{}}, problems
+	}
+	traceNode(ctx, p, "signature")
+
+	problems = append(problems, validateFieldNames(p.AtName("param"), "parameter", s.Params)...)
+	problems = append(problems, validateFieldNames(p.AtName("result"), "result", s.Results)...)
+
+	params := []*ast.Field{}
+	for i, param := range s.Params {
+		field, d := param.toAst(ctx, p.AtName("param").AtListIndex(i))
+		problems = append(problems, d...)
+		params = append(params, field)
+	}
+
+	results := []*ast.Field{}
+	for i, res := range s.Results {
+		field, d := res.toAst(ctx, p.AtName("result").AtListIndex(i))
+		problems = append(problems, d...)
+		results = append(results, field)
+	}
+
+	return &ast.FuncType{
+		Params: &ast.FieldList{
+			List: params,
+		},
+		Results: &ast.FieldList{
+			List: results,
+		},
+	}, problems
+}
+
+// validateFieldNames reports the unnamed fields in a list where others are
+// named, since Go requires either every parameter or result to be named, or
+// none.
+func validateFieldNames(p Path, kind string, fields []Field) Problems {
+	var problems Problems
+
+	named := 0
+	for _, field := range fields {
+		if field.Name != nil {
+			named++
+		}
+	}
+	if named == 0 || named == len(fields) {
+		return problems
+	}
+
+	for i, field := range fields {
+		if field.Name == nil {
+			problems.add(p.AtListIndex(i).AtName("name"), "Missing "+kind+" name", fmt.Sprintf("Either every %s must be named, or none.", kind))
+		}
+	}
+	return problems
+}
+
+// Body is a function body.
+type Body struct {
+	Statements []Statement `tfsdk:"statement" json:"statement,omitempty"`
+}
+
+func (b *Body) toAst(ctx context.Context, p Path) (*ast.BlockStmt, Problems) {
+	var problems Problems
+
+	// Functions without a body block are rendered as bodiless declarations.
+	if b == nil {
+		return nil, problems
+	}
+	traceNode(ctx, p, "body")
+
+	stmts := []ast.Stmt{}
+	for i, stmt := range b.Statements {
+		node, d := stmt.toAst(ctx, p.AtName("statement").AtListIndex(i))
+		problems = append(problems, d...)
+		stmts = append(stmts, node)
+	}
+	return &ast.BlockStmt{
+		List: stmts,
+	}, problems
+}
+
+// Statement kinds.
+const (
+	StmtExpr   = "expression"
+	StmtReturn = "return"
+)
+
+// Statement is a single statement, whose Kind is one of the Stmt constants.
+type Statement struct {
+	Kind string      `tfsdk:"kind" json:"kind,omitempty"`
+	Expr *Expression `tfsdk:"expression" json:"expression,omitempty"`
+}
+
+func (s *Statement) toAst(ctx context.Context, p Path) (ast.Stmt, Problems) {
+	traceNode(ctx, p, "statement")
+	var problems Problems
+
+	switch s.Kind {
+	case StmtExpr:
+		if s.Expr == nil {
+			problems = append(problems, missingBlock(p, "expression", s.Kind))
+			return nil, problems
+		}
+		expr, d := s.Expr.toAst(ctx, p.AtName("expression"))
+		problems = append(problems, d...)
+		return &ast.ExprStmt{X: expr}, problems
+	case StmtReturn:
+		if s.Expr == nil {
+			return &ast.ReturnStmt{}, problems
+		}
+		expr, d := s.Expr.toAst(ctx, p.AtName("expression"))
+		problems = append(problems, d...)
+		return &ast.ReturnStmt{Results: []ast.Expr{expr}}, problems
+	default:
+		problems = append(problems, unsupportedKind(p, "statement", s.Kind))
+		return nil, problems
+	}
+}
+
+// Expression kinds.
+const (
+	ExprCall       = "call"
+	ExprSelector   = "selector"
+	ExprLiteral    = "literal"
+	ExprIdentifier = "identifier"
+)
+
+// Expression is an expression whose Kind, one of the Expr constants, says
+// which of the other fields describes it.
+type Expression struct {
+	Kind       string      `tfsdk:"kind" json:"kind,omitempty"`
+	Selector   *Selector   `tfsdk:"selector" json:"selector,omitempty"`
+	Call       *Call       `tfsdk:"call" json:"call,omitempty"`
+	Literal    *Literal    `tfsdk:"literal" json:"literal,omitempty"`
+	Identifier *Identifier `tfsdk:"identifier" json:"identifier,omitempty"`
+}
+
+func (e *Expression) toAst(ctx context.Context, p Path) (ast.Expr, Problems) {
+	traceNode(ctx, p, "expression")
+	var problems Problems
+
+	switch e.Kind {
+	case ExprCall:
+		if e.Call == nil {
+			problems = append(problems, missingBlock(p, "call", e.Kind))
+			return nil, problems
+		}
+		return e.Call.toAst(ctx, p.AtName("call"))
+	case ExprSelector:
+		if e.Selector == nil {
+			problems = append(problems, missingBlock(p, "selector", e.Kind))
+			return nil, problems
+		}
+		return e.Selector.toAst(ctx, p.AtName("selector"))
+	case ExprLiteral:
+		if e.Literal == nil {
+			problems = append(problems, missingBlock(p, "literal", e.Kind))
+			return nil, problems
+		}
+		return e.Literal.toAst(ctx, p.AtName("literal"))
+	case ExprIdentifier:
+		if e.Identifier == nil {
+			problems = append(problems, missingBlock(p, "identifier", e.Kind))
+			return nil, problems
+		}
+		return e.Identifier.toAst(ctx, p.AtName("identifier"))
+	default:
+		problems = append(problems, unsupportedKind(p, "expression", e.Kind))
+		return nil, problems
+	}
+}
+
+// Identifier is a reference to a name in scope.
+type Identifier struct {
+	Name string `tfsdk:"name" json:"name,omitempty"`
+}
+
+func (i *Identifier) toAst(ctx context.Context, p Path) (ast.Expr, Problems) {
+	traceNode(ctx, p, "identifier")
+	var problems Problems
+
+	if !token.IsIdentifier(i.Name) {
+		problems.add(p.AtName("name"), "Invalid identifier", fmt.Sprintf("%q is not a valid Go identifier.", i.Name))
+	}
+
+	return ast.NewIdent(i.Name), problems
+}
+
+// Call is a function call. Its arguments are either literals, or, since
+// Terraform schemas can't be recursive, arbitrary expressions when the model
+// comes from somewhere other than HCL, such as JSON.
+type Call struct {
+	Func     *Selector    `tfsdk:"func" json:"func,omitempty"`
+	Args     []Literal    `tfsdk:"arg" json:"arg,omitempty"`
+	ArgExprs []Expression `tfsdk:"-" json:"arg_expression,omitempty"`
+}
+
+func (c *Call) toAst(ctx context.Context, p Path) (ast.Expr, Problems) {
+	traceNode(ctx, p, "call")
+	var problems Problems
+
+	if c.Func == nil {
+		problems.add(p.AtName("func"), "Missing func block", "Calls must name the function being called.")
+		return nil, problems
+	}
+	fun, d := c.Func.toAst(ctx, p.AtName("func"))
+	problems = append(problems, d...)
+
+	if len(c.Args) > 0 && len(c.ArgExprs) > 0 {
+		problems.add(p.AtName("arg_expression"), "Conflicting arguments", "Calls may have literal arguments or expression arguments, but not both.")
+	}
+
+	args := []ast.Expr{}
+	for i, arg := range c.Args {
+		expr, d := arg.toAst(ctx, p.AtName("arg").AtListIndex(i))
+		problems = append(problems, d...)
+		args = append(args, expr)
+	}
+	for i, arg := range c.ArgExprs {
+		expr, d := arg.toAst(ctx, p.AtName("arg_expression").AtListIndex(i))
+		problems = append(problems, d...)
+		args = append(args, expr)
+	}
+	return &ast.CallExpr{
+		Fun:  fun,
+		Args: args,
+	}, problems
+}
+
+// Selector is a qualified name such as fmt.Println, or, without From, a
+// local name.
+type Selector struct {
+	From *string `tfsdk:"from" json:"from,omitempty"`
+	Prop string  `tfsdk:"prop" json:"prop,omitempty"`
+}
+
+func (s *Selector) toAst(ctx context.Context, p Path) (ast.Expr, Problems) {
+	traceNode(ctx, p, "selector")
+	var problems Problems
+
+	if !token.IsIdentifier(s.Prop) {
+		problems.add(p.AtName("prop"), "Invalid selector", fmt.Sprintf("%q is not a valid Go identifier.", s.Prop))
+	}
+	problems = append(problems, validateIdent(p.AtName("from"), s.From)...)
+
+	// Without a "from", a selector is just a reference to a local name.
+	if s.From == nil {
+		return ast.NewIdent(s.Prop), problems
+	}
+
+	return &ast.SelectorExpr{
+		X:   astutil.MaybeNewIdent(s.From),
+		Sel: ast.NewIdent(s.Prop),
+	}, problems
+}
+
+// Literal kinds.
+const (
+	LitIdent  = "identifier"
+	LitString = "string"
+	LitInt    = "int"
+)
+
+// Literal is a literal value whose Kind is one of the Lit constants. String
+// values are quoted and escaped when rendered; others are used as is.
+type Literal struct {
+	Kind  string `tfsdk:"kind" json:"kind,omitempty"`
+	Value string `tfsdk:"value" json:"value,omitempty"`
+}
+
+func (l *Literal) toAst(ctx context.Context, p Path) (ast.Expr, Problems) {
+	traceNode(ctx, p, "literal")
+	var problems Problems
+
+	switch l.Kind {
+	case LitIdent:
+		return &ast.BasicLit{Kind: token.IDENT, Value: l.Value}, problems
+	case LitString:
+		return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(l.Value)}, problems
+	case LitInt:
+		if _, err := strconv.ParseInt(l.Value, 0, 64); err != nil {
+			problems.add(p.AtName("value"), "Invalid int literal", fmt.Sprintf("%q is not a valid Go integer literal.", l.Value))
+		}
+		return &ast.BasicLit{Kind: token.INT, Value: l.Value}, problems
+	default:
+		problems = append(problems, unsupportedKind(p, "literal", l.Kind))
+		return nil, problems
+	}
+}
+
+// Func is a top-level function declaration. Without a Body, it's rendered
+// as a declaration of a function implemented elsewhere, e.g. in assembly.
+type Func struct {
+	Name      string     `tfsdk:"name" json:"name,omitempty"`
+	Signature *Signature `tfsdk:"signature" json:"signature,omitempty"`
+	Body      *Body      `tfsdk:"body" json:"body,omitempty"`
+}
+
+func (f *Func) toAst(ctx context.Context, p Path) (*ast.FuncDecl, Problems) {
+	traceNode(ctx, p, "func")
+	var problems Problems
+
+	if !token.IsIdentifier(f.Name) {
+		problems.add(p.AtName("name"), "Invalid function name", fmt.Sprintf("%q is not a valid Go identifier.", f.Name))
+	}
+
+	sig, d := f.Signature.toAst(ctx, p.AtName("signature"))
+	problems = append(problems, d...)
+
+	body, d := f.Body.toAst(ctx, p.AtName("body"))
+	problems = append(problems, d...)
+
+	return &ast.FuncDecl{
+		Name: ast.NewIdent(f.Name),
+		Type: sig,
+		Body: body,
+	}, problems
+}
+
+// missingBlock reports a node whose kind requires a nested block that wasn't
+// provided, e.g. an expression of kind "call" without a call block.
+func missingBlock(p Path, block string, kind string) Problem {
+	return Problem{
+		Path:    p.AtName(block),
+		Summary: "Missing " + block + " block",
+		Detail:  fmt.Sprintf("A %s block is required when kind is %q.", block, kind),
+	}
+}
+
+func unsupportedKind(p Path, node string, kind string) Problem {
+	return Problem{
+		Path:    p.AtName("kind"),
+		Summary: "Unsupported " + node + " kind",
+		Detail:  fmt.Sprintf("%q is not a supported %s kind.", kind, node),
+	}
+}
+
+// validateIdent ensures an optional name is a valid Go identifier.
+func validateIdent(p Path, name *string) Problems {
+	var problems Problems
+	if name != nil && !token.IsIdentifier(*name) && *name != "." {
+		problems.add(p, "Invalid identifier", fmt.Sprintf("%q is not a valid Go identifier.", *name))
+	}
+	return problems
+}
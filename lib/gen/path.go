@@ -0,0 +1,97 @@
+package gen
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// Path locates a block or attribute within a File, using the same names as
+// the HCL configuration it mirrors, e.g. func[0].body.statement[1].
+type Path []PathStep
+
+// PathStep is one step of a Path: either a named attribute or block, or, if
+// Name is empty, an index into a list of blocks.
+type PathStep struct {
+	Name  string
+	Index int
+}
+
+// Root returns the path to a top-level attribute or block of a File.
+func Root(name string) Path {
+	return Path{{Name: name}}
+}
+
+// AtName returns the path to a nested attribute or block.
+func (p Path) AtName(name string) Path {
+	return append(p[:len(p):len(p)], PathStep{Name: name})
+}
+
+// AtListIndex returns the path to an element of a list of blocks.
+func (p Path) AtListIndex(i int) Path {
+	return append(p[:len(p):len(p)], PathStep{Index: i})
+}
+
+func (p Path) String() string {
+	b := new(strings.Builder)
+	for i, step := range p {
+		if step.Name == "" {
+			b.WriteString("[" + strconv.Itoa(step.Index) + "]")
+			continue
+		}
+		if i > 0 {
+			b.WriteString(".")
+		}
+		b.WriteString(step.Name)
+	}
+	return b.String()
+}
+
+// Problem describes an invalid part of a model.
+type Problem struct {
+	Path    Path
+	Summary string
+	Detail  string
+}
+
+func (p Problem) Error() string {
+	return p.Path.String() + ": " + p.Detail
+}
+
+// Problems is the error returned when a model can't be converted. Every
+// problem in the model is reported, not just the first.
+type Problems []Problem
+
+func (ps Problems) Error() string {
+	msgs := make([]string, len(ps))
+	for i, p := range ps {
+		msgs[i] = p.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (ps *Problems) add(p Path, summary string, detail string) {
+	*ps = append(*ps, Problem{Path: p, Summary: summary, Detail: detail})
+}
+
+// A Tracer is told about each step of a conversion, for debugging. When a
+// step concerns a block of the model, fields["path"] holds its Path.
+type Tracer func(msg string, fields map[string]interface{})
+
+type tracerKey struct{}
+
+// WithTracer returns a context that makes conversions report their progress
+// to t.
+func WithTracer(ctx context.Context, t Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, t)
+}
+
+func trace(ctx context.Context, msg string, fields map[string]interface{}) {
+	if t, ok := ctx.Value(tracerKey{}).(Tracer); ok {
+		t(msg, fields)
+	}
+}
+
+func traceNode(ctx context.Context, p Path, node string) {
+	trace(ctx, "Converting "+node+" to AST", map[string]interface{}{"path": p})
+}
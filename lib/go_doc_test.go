@@ -0,0 +1,70 @@
+package caiac
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGoDocDataSource(t *testing.T) {
+	baseDir := t.TempDir()
+	src := `// Package greet says hello.
+package greet
+
+// Hello greets name.
+//
+// # Empty names
+//
+// An empty name is greeted all the same.
+func Hello(name string) string { return "Hello, " + name }
+`
+	if err := os.MkdirAll(filepath.Join(baseDir, "greet"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(baseDir, "greet", "greet.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	markdown := "# package greet\n\n" +
+		"```go\nimport \"example.com/greet\"\n```\n\n" +
+		"Package greet says hello.\n\n" +
+		"## Functions\n\n" +
+		"### func Hello\n\n" +
+		"```go\nfunc Hello(name string) string\n```\n\n" +
+		"Hello greets name.\n\n" +
+		"#### Empty names {#hdr-Empty_names}\n\n" +
+		"An empty name is greeted all the same.\n"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// HTML is only rendered on request, and headings in doc comments
+			// are nested under the declaration they document.
+			{
+				Config: testAccProviderConfig(baseDir) + `
+data "caiac_go_doc" "greet" {
+  directory   = "greet"
+  import_path = "example.com/greet"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.caiac_go_doc.greet", "markdown", markdown),
+					resource.TestCheckNoResourceAttr("data.caiac_go_doc.greet", "html"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(baseDir) + `
+data "caiac_go_doc" "greet" {
+  directory   = "greet"
+  import_path = "example.com/greet"
+  render_html = true
+}
+`,
+				Check: resource.TestMatchResourceAttr("data.caiac_go_doc.greet", "html", regexp.MustCompile(`<h3>func Hello</h3>[\s\S]*<h4 id="hdr-Empty_names">Empty names</h4>`)),
+			},
+		},
+	})
+}
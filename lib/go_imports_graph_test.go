@@ -0,0 +1,49 @@
+package caiac
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGoImportsGraphDataSourceSkipsNestedModules(t *testing.T) {
+	baseDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":           "module example.com/app\n",
+		"main.go":          "package main\n\nimport _ \"example.com/app/greet\"\n",
+		"greet/greet.go":   "package greet\n",
+		"tools/go.mod":     "module example.com/tools\n",
+		"tools/tools.go":   "package tools\n\nimport _ \"example.com/app\"\n",
+		"tools/sub/sub.go": "package sub\n",
+	}
+	for name, contents := range files {
+		filename := filepath.Join(baseDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(baseDir) + `
+data "caiac_go_imports_graph" "app" {}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.caiac_go_imports_graph.app", "module_path", "example.com/app"),
+					resource.TestCheckResourceAttr("data.caiac_go_imports_graph.app", "packages.#", "2"),
+					resource.TestCheckResourceAttr("data.caiac_go_imports_graph.app", "packages.0.path", "example.com/app"),
+					resource.TestCheckResourceAttr("data.caiac_go_imports_graph.app", "packages.1.path", "example.com/app/greet"),
+					resource.TestCheckResourceAttr("data.caiac_go_imports_graph.app", "edges.#", "1"),
+					resource.TestCheckResourceAttr("data.caiac_go_imports_graph.app", "cycles.#", "0"),
+				),
+			},
+		},
+	})
+}
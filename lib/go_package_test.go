@@ -0,0 +1,86 @@
+package caiac
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccGoPackageResourceRepairsFiles checks that files changed or removed
+// outside of Terraform are rewritten by the next apply.
+func TestAccGoPackageResourceRepairsFiles(t *testing.T) {
+	baseDir := t.TempDir()
+	greet := filepath.Join(baseDir, "greet", "greet.go")
+	doc := filepath.Join(baseDir, "greet", "doc.go")
+	greetContents := "package greet\n\nimport \"fmt\"\n\nfunc Print() {\n\tfmt.Println(\"Hello, world!\")\n}\n"
+	docContents := "// Package greet says hello.\npackage greet\n"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(baseDir) + testAccGoPackageConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckFileContents(greet, greetContents),
+					testAccCheckFileContents(doc, docContents),
+				),
+			},
+			{
+				PreConfig: func() {
+					if err := os.Remove(greet); err != nil {
+						t.Fatal(err)
+					}
+					if err := os.WriteFile(doc, []byte("package greet\n"), 0o644); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccProviderConfig(baseDir) + testAccGoPackageConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckFileContents(greet, greetContents),
+					testAccCheckFileContents(doc, docContents),
+				),
+			},
+		},
+	})
+}
+
+const testAccGoPackageConfig = `
+resource "caiac_go_package" "test" {
+  directory    = "greet"
+  package_name = "greet"
+  doc          = "Package greet says hello."
+
+  file {
+    name = "greet.go"
+
+    import {
+      path = "fmt"
+    }
+
+    func {
+      name = "Print"
+
+      body {
+        statement {
+          kind = "expression"
+          expression {
+            kind = "call"
+            call {
+              func {
+                from = "fmt"
+                prop = "Println"
+              }
+              arg {
+                kind  = "string"
+                value = "Hello, world!"
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`
@@ -0,0 +1,297 @@
+package caiac
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccGoSourceResource(t *testing.T) {
+	baseDir := t.TempDir()
+	filename := filepath.Join(baseDir, "cmd", "hello", "main.go")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			// Delete removes the directories it leaves empty, but never the
+			// base directory itself.
+			for _, dir := range []string{filename, filepath.Dir(filename), filepath.Join(baseDir, "cmd")} {
+				if _, err := os.Stat(dir); !errors.Is(err, fs.ErrNotExist) {
+					return fmt.Errorf("%s still exists after destroy", dir)
+				}
+			}
+			if _, err := os.Stat(baseDir); err != nil {
+				return fmt.Errorf("base directory was removed: %w", err)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			// Create.
+			{
+				Config: testAccProviderConfig(baseDir) + testAccGoSourceConfig("Hello, world"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("caiac_go_source.test", "filename", "cmd/hello/main.go"),
+					resource.TestCheckResourceAttr("caiac_go_source.test", "contents", testAccGoSourceContents("Hello, world")),
+					resource.TestCheckResourceAttrWith("caiac_go_source.test", "diff", testAccCheckDiff(
+						"--- /dev/null\n+++ b/cmd/hello/main.go\n",
+						"+\tfmt.Println(\"Hello, world\")\n",
+					)),
+					testAccCheckFileContents(filename, testAccGoSourceContents("Hello, world")),
+				),
+			},
+			// Update in place.
+			{
+				Config: testAccProviderConfig(baseDir) + testAccGoSourceConfig("Hello again"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("caiac_go_source.test", "contents", testAccGoSourceContents("Hello again")),
+					resource.TestCheckResourceAttrWith("caiac_go_source.test", "diff", testAccCheckDiff(
+						"--- a/cmd/hello/main.go\n+++ b/cmd/hello/main.go\n",
+						"-\tfmt.Println(\"Hello, world\")\n+\tfmt.Println(\"Hello again\")\n",
+					)),
+					testAccCheckFileContents(filename, testAccGoSourceContents("Hello again")),
+				),
+			},
+			// Changes made on-disk are planned away.
+			{
+				PreConfig: func() {
+					if err := os.WriteFile(filename, []byte("package main\n"), 0o644); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccProviderConfig(baseDir) + testAccGoSourceConfig("Hello again"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("caiac_go_source.test", "diff", testAccCheckDiff(
+						"+// caiac:fingerprint ",
+						" package main\n+\n+import \"fmt\"\n",
+					)),
+					testAccCheckFileContents(filename, testAccGoSourceContents("Hello again")),
+				),
+			},
+			// Import by filename. Declarations aren't read back into blocks.
+			{
+				ResourceName:                         "caiac_go_source.test",
+				ImportState:                          true,
+				ImportStateId:                        "cmd/hello/main.go",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "filename",
+				ImportStateVerifyIgnore:              []string{"import", "func", "diff"},
+			},
+			// Read the file back with the data source.
+			{
+				Config: testAccProviderConfig(baseDir) + testAccGoSourceConfig("Hello again") + `
+data "caiac_go_source" "test" {
+  filename = caiac_go_source.test.filename
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.caiac_go_source.test", "contents", "caiac_go_source.test", "contents"),
+					resource.TestCheckResourceAttr("data.caiac_go_source.test", "package_name", "main"),
+					resource.TestCheckResourceAttr("data.caiac_go_source.test", "imports.#", "1"),
+					resource.TestCheckResourceAttr("data.caiac_go_source.test", "imports.0.path", "fmt"),
+					resource.TestCheckResourceAttr("data.caiac_go_source.test", "funcs.#", "1"),
+					resource.TestCheckResourceAttr("data.caiac_go_source.test", "funcs.0.name", "main"),
+				),
+			},
+			// Renaming replaces the file.
+			{
+				Config: testAccProviderConfig(baseDir) + strings.Replace(testAccGoSourceConfig("Hello again"), "cmd/hello/main.go", "cmd/hello/hello.go", 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("caiac_go_source.test", "filename", "cmd/hello/hello.go"),
+					func(*terraform.State) error {
+						if _, err := os.Stat(filename); !errors.Is(err, fs.ErrNotExist) {
+							return fmt.Errorf("%s still exists after renaming", filename)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+// TestAccGoSourceResourceDeleteKeepsNonEmptyDirs checks that deleting one
+// file leaves the directories still holding others.
+func TestAccGoSourceResourceDeleteKeepsNonEmptyDirs(t *testing.T) {
+	baseDir := t.TempDir()
+	sibling := filepath.Join(baseDir, "cmd", "README.md")
+	if err := os.MkdirAll(filepath.Dir(sibling), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(sibling, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			if _, err := os.Stat(filepath.Join(baseDir, "cmd", "hello")); !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("empty directory wasn't removed")
+			}
+			if _, err := os.Stat(sibling); err != nil {
+				return fmt.Errorf("unmanaged file was affected: %w", err)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(baseDir) + testAccGoSourceConfig("Hello, world"),
+				Check:  testAccCheckFileContents(filepath.Join(baseDir, "cmd", "hello", "main.go"), testAccGoSourceContents("Hello, world")),
+			},
+		},
+	})
+}
+
+// TestAccGoSourceResourceOwnership checks that hand-written files are neither
+// overwritten on create, unless overwrite is set, nor deleted once changed.
+func TestAccGoSourceResourceOwnership(t *testing.T) {
+	baseDir := t.TempDir()
+	filename := filepath.Join(baseDir, "cmd", "hello", "main.go")
+	renamed := filepath.Join(baseDir, "cmd", "hello", "hello.go")
+	handWritten := "package main\n\nfunc main() {}\n"
+	if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filename, []byte(handWritten), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	overwrite := strings.Replace(testAccGoSourceConfig("Hello, world"), `package_name = "main"`, `package_name = "main"
+  overwrite    = true`, 1)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// A hand-written file isn't replaced by default.
+			{
+				Config:      testAccProviderConfig(baseDir) + testAccGoSourceConfig("Hello, world"),
+				ExpectError: regexp.MustCompile(`wasn't generated by\s+this\s+provider`),
+				Check:       testAccCheckFileContents(filename, handWritten),
+			},
+			// Nor is a generated file that has since been edited by hand.
+			{
+				PreConfig: func() {
+					edited := testAccGoSourceContents("Hello, world") + "\nfunc edited() {}\n"
+					if err := os.WriteFile(filename, []byte(edited), 0o644); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:      testAccProviderConfig(baseDir) + testAccGoSourceConfig("Hello, world"),
+				ExpectError: regexp.MustCompile(`no longer matches the\s+fingerprint`),
+			},
+			// It is with overwrite set.
+			{
+				Config: testAccProviderConfig(baseDir) + overwrite,
+				Check:  testAccCheckFileContents(filename, testAccGoSourceContents("Hello, world")),
+			},
+			// Once edited by hand, the file isn't deleted.
+			{
+				PreConfig: func() {
+					if err := os.WriteFile(filename, []byte(testAccGoSourceContents("Hello, world")+"\nfunc edited() {}\n"), 0o644); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:      testAccProviderConfig(baseDir) + overwrite,
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`no longer matches the\s+fingerprint`),
+			},
+			// Applying restores it, after which it can be deleted.
+			{
+				Config: testAccProviderConfig(baseDir) + overwrite,
+				Check:  testAccCheckFileContents(filename, testAccGoSourceContents("Hello, world")),
+			},
+			// Renaming replaces the resource, so a hand-written file at the
+			// new name isn't replaced either.
+			{
+				PreConfig: func() {
+					if err := os.WriteFile(renamed, []byte(handWritten), 0o644); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:      testAccProviderConfig(baseDir) + strings.Replace(testAccGoSourceConfig("Hello, world"), "cmd/hello/main.go", "cmd/hello/hello.go", 1),
+				ExpectError: regexp.MustCompile(`wasn't generated by\s+this\s+provider`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckFileContents(renamed, handWritten),
+				),
+			},
+		},
+	})
+}
+
+func testAccGoSourceConfig(greeting string) string {
+	return fmt.Sprintf(`
+resource "caiac_go_source" "test" {
+  filename     = "cmd/hello/main.go"
+  package_name = "main"
+
+  import {
+    path = "fmt"
+  }
+
+  func {
+    name = "main"
+
+    body {
+      statement {
+        kind = "expression"
+        expression {
+          kind = "call"
+          call {
+            func {
+              from = "fmt"
+              prop = "Println"
+            }
+            arg {
+              kind  = "string"
+              value = %q
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`, greeting)
+}
+
+// testAccGoSourceContents returns the file written for testAccGoSourceConfig,
+// including the generated-code header.
+func testAccGoSourceContents(greeting string) string {
+	contents := fmt.Sprintf("package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(%q)\n}\n", greeting)
+	sum := sha256.Sum256([]byte("cmd/hello/main.go\x00" + contents))
+	return "// Code generated by terraform-provider-caiac. DO NOT EDIT.\n// caiac:fingerprint " + hex.EncodeToString(sum[:16]) + "\n\n" + contents
+}
+
+func testAccCheckFileContents(filename string, want string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		got, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		if string(got) != want {
+			return fmt.Errorf("%s contains:\n%s\nwant:\n%s", filename, got, want)
+		}
+		return nil
+	}
+}
+
+// testAccCheckDiff checks that the diff attribute contains each of hunks.
+func testAccCheckDiff(hunks ...string) resource.CheckResourceAttrWithFunc {
+	return func(diff string) error {
+		for _, hunk := range hunks {
+			if !strings.Contains(diff, hunk) {
+				return fmt.Errorf("diff doesn't contain:\n%s\ngot:\n%s", hunk, diff)
+			}
+		}
+		return nil
+	}
+}
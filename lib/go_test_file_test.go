@@ -0,0 +1,107 @@
+package caiac
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGoTestResourceOwnership(t *testing.T) {
+	baseDir := t.TempDir()
+	filename := filepath.Join(baseDir, "p", "p_test.go")
+	handWritten := "package p\n"
+	if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filename, []byte(handWritten), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	overwrite := strings.Replace(testAccGoTestConfig, `package_name = "p"`, `package_name = "p"
+  overwrite    = true`, 1)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// A hand-written file isn't replaced by default.
+			{
+				Config:      testAccProviderConfig(baseDir) + testAccGoTestConfig,
+				ExpectError: regexp.MustCompile(`wasn't generated by\s+this\s+provider`),
+				Check:       testAccCheckFileContents(filename, handWritten),
+			},
+			// It is with overwrite set.
+			{
+				Config: testAccProviderConfig(baseDir) + overwrite,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckFileContents(filename, testAccGoTestContents("p/p_test.go")),
+					resource.TestCheckResourceAttr("caiac_go_test.test", "contents", testAccGoTestContents("p/p_test.go")),
+				),
+			},
+			// A file edited by hand is planned to be rewritten.
+			{
+				PreConfig: func() {
+					if err := os.WriteFile(filename, []byte(testAccGoTestContents("p/p_test.go")+"\nfunc edited() {}\n"), 0o644); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccProviderConfig(baseDir) + overwrite,
+				Check:  testAccCheckFileContents(filename, testAccGoTestContents("p/p_test.go")),
+			},
+			// Once edited by hand, the file isn't deleted.
+			{
+				PreConfig: func() {
+					if err := os.WriteFile(filename, []byte(testAccGoTestContents("p/p_test.go")+"\nfunc edited() {}\n"), 0o644); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:      testAccProviderConfig(baseDir) + overwrite,
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`no longer matches the\s+fingerprint`),
+			},
+			// Applying restores it.
+			{
+				Config: testAccProviderConfig(baseDir) + overwrite,
+				Check:  testAccCheckFileContents(filename, testAccGoTestContents("p/p_test.go")),
+			},
+			// A renamed file doesn't replace a hand-written file either.
+			{
+				PreConfig: func() {
+					if err := os.WriteFile(filepath.Join(baseDir, "p", "q_test.go"), []byte(handWritten), 0o644); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:      testAccProviderConfig(baseDir) + strings.Replace(testAccGoTestConfig, "p/p_test.go", "p/q_test.go", 1),
+				ExpectError: regexp.MustCompile(`wasn't generated by\s+this\s+provider`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckFileContents(filepath.Join(baseDir, "p", "q_test.go"), handWritten),
+					testAccCheckFileContents(filename, testAccGoTestContents("p/p_test.go")),
+				),
+			},
+		},
+	})
+}
+
+const testAccGoTestConfig = `
+resource "caiac_go_test" "test" {
+  filename     = "p/p_test.go"
+  package_name = "p"
+
+  test {
+    name = "Nothing"
+  }
+}
+`
+
+// testAccGoTestContents returns the file written for testAccGoTestConfig,
+// including the generated-code header.
+func testAccGoTestContents(filename string) string {
+	contents := "package p\n\nimport \"testing\"\n\nfunc TestNothing(t *testing.T) {\n}\n"
+	sum := sha256.Sum256([]byte(filename + "\x00" + contents))
+	return "// Code generated by terraform-provider-caiac. DO NOT EDIT.\n// caiac:fingerprint " + hex.EncodeToString(sum[:16]) + "\n\n" + contents
+}
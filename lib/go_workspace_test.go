@@ -0,0 +1,84 @@
+package caiac
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccGoWorkspaceResourceOwnership(t *testing.T) {
+	baseDir := t.TempDir()
+	filename := filepath.Join(baseDir, "go.work")
+	handWritten := "go 1.21\n"
+	if err := os.MkdirAll(filepath.Join(baseDir, "greet"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(baseDir, "greet", "go.mod"), []byte("module example.com/greet\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filename, []byte(handWritten), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	overwrite := strings.Replace(testAccGoWorkspaceConfig, `go_version = "1.21"`, `go_version = "1.21"
+  overwrite  = true`, 1)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// A hand-written go.work isn't replaced by default.
+			{
+				Config:      testAccProviderConfig(baseDir) + testAccGoWorkspaceConfig,
+				ExpectError: regexp.MustCompile(`wasn't generated by\s+this\s+provider`),
+				Check:       testAccCheckFileContents(filename, handWritten),
+			},
+			// It is with overwrite set.
+			{
+				Config: testAccProviderConfig(baseDir) + overwrite,
+				Check:  testAccCheckGoWorkspace(filename),
+			},
+			// A go.work deleted outside of Terraform is recreated.
+			{
+				PreConfig: func() {
+					if err := os.Remove(filename); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccProviderConfig(baseDir) + testAccGoWorkspaceConfig,
+				Check:  testAccCheckGoWorkspace(filename),
+			},
+		},
+	})
+}
+
+const testAccGoWorkspaceConfig = `
+resource "caiac_go_workspace" "test" {
+  directory  = "."
+  go_version = "1.21"
+
+  use {
+    path = "./greet"
+  }
+}
+`
+
+// testAccCheckGoWorkspace checks that filename is the go.work written for
+// testAccGoWorkspaceConfig, with the generated-code header.
+func testAccCheckGoWorkspace(filename string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		got, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(string(got), "// Code generated by terraform-provider-caiac. DO NOT EDIT.\n") || !strings.Contains(string(got), "use ./greet\n") {
+			return fmt.Errorf("%s contains:\n%s", filename, got)
+		}
+		return nil
+	}
+}
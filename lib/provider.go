@@ -40,6 +40,7 @@ func (p *caiacProvider) Metadata(_ context.Context, _ provider.MetadataRequest,
 // Schema defines the provider-level schema for configuration data.
 func (p *caiacProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Description: "Manages Go source code, modules, and workspaces as infrastructure, and reads existing code for use in configuration.",
 		Attributes: map[string]schema.Attribute{
 			"base_dir": schema.StringAttribute{
 				Optional:    true,
@@ -79,17 +80,19 @@ func (p *caiacProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		baseDir = config.BaseDir.ValueString()
 	}
 
-	baseDir, err := os.Getwd()
-
-	if err != nil {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("base_dir"),
-			"Missing CaIaC base directory",
-			"The CaIaC provider requires a base directory to know where files are, "+
-				" but one wasn't provided and the current working directory couldn't be found: "+
-				err.Error(),
-		)
-		return
+	if baseDir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("base_dir"),
+				"Missing CaIaC base directory",
+				"The CaIaC provider requires a base directory to know where files are, "+
+					" but one wasn't provided and the current working directory couldn't be found: "+
+					err.Error(),
+			)
+			return
+		}
+		baseDir = wd
 	}
 
 	resp.DataSourceData = &datasources.DataSourceData{BaseDir: baseDir}
@@ -100,6 +103,11 @@ func (p *caiacProvider) Configure(ctx context.Context, req provider.ConfigureReq
 func (p *caiacProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		datasources.NewGoSourceDataSource,
+		datasources.NewGoPackageDataSource,
+		datasources.NewGoModuleDataSource,
+		datasources.NewGoImportsGraphDataSource,
+		datasources.NewGoMetricsDataSource,
+		datasources.NewGoDocDataSource,
 	}
 }
 
@@ -107,5 +115,10 @@ func (p *caiacProvider) DataSources(_ context.Context) []func() datasource.DataS
 func (p *caiacProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		resources.NewGoSourceResource,
+		resources.NewGoPackageResource,
+		resources.NewGoModuleResource,
+		resources.NewGoWorkspaceResource,
+		resources.NewGoTestResource,
+		resources.NewGoTestRunResource,
 	}
 }
\ No newline at end of file
@@ -0,0 +1,25 @@
+package caiac
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// testAccProtoV6ProviderFactories serves the provider in-process to the
+// Terraform binary run by acceptance tests. Acceptance tests only run with
+// TF_ACC set, and use the Terraform binary found on PATH, or named by
+// TF_ACC_TERRAFORM_PATH.
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"caiac": providerserver.NewProtocol6WithError(New()),
+}
+
+// testAccProviderConfig configures the provider to work within baseDir.
+func testAccProviderConfig(baseDir string) string {
+	return fmt.Sprintf(`
+provider "caiac" {
+  base_dir = %q
+}
+`, baseDir)
+}
@@ -1,5 +1,45 @@
 package resources
 
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
 type ResourceData struct {
 	BaseDir string
 }
+
+// removeEmptyDirs removes dir and each of its parents, stopping at the first
+// directory that isn't empty or when stop is reached. The stop directory
+// itself is never removed.
+func removeEmptyDirs(ctx context.Context, dir string, stop string, diags *diag.Diagnostics) {
+	stop = filepath.Clean(stop)
+	for dir = filepath.Clean(dir); dir != stop && dir != "/" && dir != "."; dir = filepath.Dir(dir) {
+		ctx = tflog.SetField(ctx, "dir", dir)
+		tflog.Debug(ctx, "empty-dir removal loop")
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			diags.AddError(
+				"Error searching for empty directories to remove",
+				"Unable to find empty directories to remove after file deletion: "+err.Error(),
+			)
+			return
+		}
+
+		if len(entries) > 0 {
+			return
+		}
+
+		if err := os.Remove(dir); err != nil {
+			diags.AddError(
+				"Error removing empty directory",
+				"Unable to remove empty directory after file deletion: "+err.Error(),
+			)
+			return
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package resources
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// unifiedDiff returns a unified diff from the snapshotted file to contents,
+// or "" if they're the same. A file that doesn't exist yet is diffed as
+// empty, from /dev/null.
+func unifiedDiff(name string, before *snapshotFile, contents string, diags *diag.Diagnostics) string {
+	from := "a/" + name
+	if !before.existed {
+		from = "/dev/null"
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(string(before.contents)),
+		B:        splitLines(contents),
+		FromFile: from,
+		ToFile:   "b/" + name,
+		Context:  3,
+	})
+	if err != nil {
+		diags.AddError(
+			"Error computing diff",
+			"Unable to diff the file on-disk with the rendered content: "+err.Error(),
+		)
+		return ""
+	}
+	return diff
+}
+
+// splitLines splits s after each newline. Unlike difflib.SplitLines, it
+// doesn't add an empty line after the final newline.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	lines[len(lines)-1] += "\n"
+	return lines
+}
@@ -0,0 +1,77 @@
+package resources
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// notRendered lists the examples of resources RenderConfig can't render,
+// with the reason why.
+var notRendered = map[string]string{
+	"caiac_go_test_run":  "runs go test rather than writing files",
+	"caiac_go_workspace": "checks the go.mod of each module it uses on-disk",
+}
+
+// TestExamples renders the documented examples and compares each rendered
+// file with its <file>.golden next to the example, as shown in the docs.
+// Every example must render at least one file, unless it's listed in
+// notRendered.
+func TestExamples(t *testing.T) {
+	dirs, err := filepath.Glob(filepath.Join("..", "..", "examples", "resources", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dir := range dirs {
+		dir := dir
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			if reason, ok := notRendered[filepath.Base(dir)]; ok {
+				t.Skipf("%s can't be rendered without Terraform: it %s", filepath.Base(dir), reason)
+			}
+
+			files, diags := RenderConfig(context.Background(), []string{filepath.Join(dir, "resource.tf")})
+			for _, d := range diags {
+				t.Errorf("%s: %s", d.Summary(), d.Detail())
+			}
+			if len(files) == 0 {
+				t.Errorf("%s renders no files", dir)
+			}
+
+			goldens, err := filepath.Glob(filepath.Join(dir, "*.golden"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			unused := map[string]bool{}
+			for _, golden := range goldens {
+				unused[golden] = true
+			}
+
+			for _, file := range files {
+				golden := filepath.Join(dir, filepath.Base(file.Filename)+".golden")
+				delete(unused, golden)
+
+				if *update {
+					if err := os.WriteFile(golden, []byte(file.Contents), 0o644); err != nil {
+						t.Fatal(err)
+					}
+					continue
+				}
+
+				want, err := os.ReadFile(golden)
+				if err != nil {
+					t.Errorf("%s: %s", file.Address, err)
+					continue
+				}
+				if file.Contents != string(want) {
+					t.Errorf("%s renders differently from %s:\n%s", file.Address, golden, file.Contents)
+				}
+			}
+
+			for golden := range unused {
+				t.Errorf("%s doesn't match any file rendered by the example", golden)
+			}
+		})
+	}
+}
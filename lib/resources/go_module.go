@@ -0,0 +1,247 @@
+package resources
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &goModuleResource{}
+	_ resource.ResourceWithConfigure   = &goModuleResource{}
+	_ resource.ResourceWithImportState = &goModuleResource{}
+)
+
+func NewGoModuleResource() resource.Resource {
+	return &goModuleResource{}
+}
+
+type goModuleResource struct {
+	baseDir string
+}
+
+func (r *goModuleResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	rd, ok := req.ProviderData.(*ResourceData)
+	if !ok {
+		return
+	}
+
+	r.baseDir = rd.BaseDir
+}
+
+func (r *goModuleResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_go_module"
+}
+
+func (r *goModuleResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a go.mod file.",
+		Attributes: map[string]schema.Attribute{
+			"directory": schema.StringAttribute{
+				Required:    true,
+				Description: "The module's root directory, relative to the provider's base directory.",
+			},
+			"module_path": schema.StringAttribute{
+				Required:    true,
+				Description: "The module path declared by the module directive.",
+			},
+			"go_version": schema.StringAttribute{
+				Optional:    true,
+				Description: "The Go language version declared by the go directive, e.g. \"1.21\".",
+			},
+			"toolchain": schema.StringAttribute{
+				Optional:    true,
+				Description: "The suggested toolchain declared by the toolchain directive, e.g. \"go1.21.3\".",
+			},
+			"contents": schema.StringAttribute{
+				Computed:    true,
+				Description: "The rendered go.mod as it exists on-disk.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"require": schema.ListNestedBlock{
+				NestedObject: *Require,
+				Description:  "A require directive.",
+			},
+			"replace": schema.ListNestedBlock{
+				NestedObject: *Replace,
+				Description:  "A replace directive.",
+			},
+			"exclude": schema.ListNestedBlock{
+				NestedObject: *Exclude,
+				Description:  "An exclude directive.",
+			},
+			"retract": schema.ListNestedBlock{
+				NestedObject: *Retract,
+				Description:  "A retract directive, for a single version or a range.",
+			},
+		},
+	}
+}
+
+func (r *goModuleResource) filename(model *goModuleResourceModel) string {
+	return filepath.Join(r.baseDir, model.Directory.ValueString(), "go.mod")
+}
+
+func (r *goModuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan goModuleResourceModel
+	{
+		diags := req.Plan.Get(ctx, &plan)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	contents := renderGoModule(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	path := r.filename(&plan)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating directories",
+			"Unable to create directory to hold go.mod: "+err.Error(),
+		)
+		return
+	}
+
+	if err := os.WriteFile(path, []byte(contents), os.ModePerm); err != nil {
+		resp.Diagnostics.AddError(
+			"Error writing file",
+			"Unable to write go.mod to disk: "+err.Error(),
+		)
+		return
+	}
+
+	plan.Contents = types.StringValue(contents)
+
+	{
+		diags := resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *goModuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state goModuleResourceModel
+	{
+		diags := req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	path := r.filename(&state)
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading file",
+			"Unable to read go.mod from disk: "+err.Error(),
+		)
+		return
+	}
+
+	prior := state
+	parseGoModule(path, contents, &state, &prior, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	{
+		diags := resp.State.Set(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *goModuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state goModuleResourceModel
+	{
+		diags := req.Plan.Get(ctx, &plan)
+		resp.Diagnostics.Append(diags...)
+		diags = req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	contents := renderGoModule(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	path := r.filename(&plan)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating directories",
+			"Unable to create directory to hold go.mod: "+err.Error(),
+		)
+		return
+	}
+
+	if err := os.WriteFile(path, []byte(contents), os.ModePerm); err != nil {
+		resp.Diagnostics.AddError(
+			"Error writing file",
+			"Unable to write go.mod to disk: "+err.Error(),
+		)
+		return
+	}
+
+	// If the module moved, clean up after its old location.
+	if oldPath := r.filename(&state); oldPath != path {
+		r.remove(ctx, oldPath, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	plan.Contents = types.StringValue(contents)
+
+	{
+		diags := resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *goModuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state goModuleResourceModel
+	{
+		diags := req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.remove(ctx, r.filename(&state), &resp.Diagnostics)
+}
+
+// ImportState adopts an existing go.mod, identified by the directory holding
+// it relative to the provider's base directory.
+func (r *goModuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("directory"), req, resp)
+}
+
+func (r *goModuleResource) remove(ctx context.Context, filename string, diags *diag.Diagnostics) {
+	if err := os.Remove(filename); err != nil {
+		diags.AddError(
+			"Error removing file",
+			"Unable to delete go.mod from disk: "+err.Error(),
+		)
+		return
+	}
+
+	removeEmptyDirs(ctx, filepath.Dir(filename), r.baseDir, diags)
+}
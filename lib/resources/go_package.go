@@ -0,0 +1,339 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource               = &goPackageResource{}
+	_ resource.ResourceWithConfigure  = &goPackageResource{}
+	_ resource.ResourceWithModifyPlan = &goPackageResource{}
+)
+
+func NewGoPackageResource() resource.Resource {
+	return &goPackageResource{}
+}
+
+type goPackageResource struct {
+	baseDir string
+}
+
+func (r *goPackageResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	rd, ok := req.ProviderData.(*ResourceData)
+	if !ok {
+		return
+	}
+
+	r.baseDir = rd.BaseDir
+}
+
+func (r *goPackageResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_go_package"
+}
+
+func (r *goPackageResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the Go source files of a package, keeping their package clauses consistent and removing files that are no longer declared.",
+		Attributes: map[string]schema.Attribute{
+			"directory": schema.StringAttribute{
+				Required:    true,
+				Description: "The package's directory, relative to the provider's base directory.",
+			},
+			"package_name": schema.StringAttribute{
+				Required:    true,
+				Description: "The name shared by every file in the package.",
+			},
+			"doc": schema.StringAttribute{
+				Optional:    true,
+				Description: "Package documentation, rendered as the package comment in doc.go.",
+			},
+			"contents": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "The rendered content of each file managed by this resource, keyed by filename.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"file": schema.ListNestedBlock{
+				NestedObject: *File,
+				Description:  "A source file in the package.",
+			},
+		},
+	}
+}
+
+// ModifyPlan renders the package's files into the planned contents, so that
+// files changed or removed on-disk since the last apply, which Read records
+// in state, are planned to be rewritten.
+func (r *goPackageResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing is rendered when destroying, or while the configuration
+	// depends on values known only after apply.
+	if req.Plan.Raw.IsNull() || !req.Config.Raw.IsFullyKnown() {
+		return
+	}
+
+	var plan goPackageResourceModel
+	{
+		diags := req.Plan.Get(ctx, &plan)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	files := renderGoPackage(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	{
+		contents, diags := types.MapValueFrom(ctx, types.StringType, files)
+		resp.Diagnostics.Append(diags...)
+		plan.Contents = contents
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	{
+		diags := resp.Plan.Set(ctx, &plan)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *goPackageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan goPackageResourceModel
+	{
+		diags := req.Plan.Get(ctx, &plan)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	files := renderGoPackage(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dir := filepath.Join(r.baseDir, plan.Directory.ValueString())
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating directories",
+			"Unable to create package directory: "+err.Error(),
+		)
+		return
+	}
+
+	r.writeFiles(ctx, &plan, dir, files, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	{
+		diags := resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *goPackageResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state goPackageResourceModel
+	{
+		diags := req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	managed := map[string]string{}
+	{
+		diags := state.Contents.ElementsAs(ctx, &managed, false)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dir := filepath.Join(r.baseDir, state.Directory.ValueString())
+	files := map[string]string{}
+	for name := range managed {
+		contents, err := os.ReadFile(filepath.Join(dir, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			// Files removed out-of-band are dropped from state, so that
+			// ModifyPlan plans to recreate them.
+			continue
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error reading file",
+				"Unable to read file from disk: "+err.Error(),
+			)
+			return
+		}
+		files[name] = string(contents)
+	}
+
+	{
+		contents, diags := types.MapValueFrom(ctx, types.StringType, files)
+		resp.Diagnostics.Append(diags...)
+		state.Contents = contents
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	{
+		diags := resp.State.Set(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *goPackageResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state goPackageResourceModel
+	{
+		diags := req.Plan.Get(ctx, &plan)
+		resp.Diagnostics.Append(diags...)
+		diags = req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	files := renderGoPackage(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dir := filepath.Join(r.baseDir, plan.Directory.ValueString())
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating directories",
+			"Unable to create package directory: "+err.Error(),
+		)
+		return
+	}
+
+	r.writeFiles(ctx, &plan, dir, files, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Remove files this resource previously created that are no longer part
+	// of the package, including everything in the old directory if it moved.
+	previous := map[string]string{}
+	{
+		diags := state.Contents.ElementsAs(ctx, &previous, false)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	oldDir := filepath.Join(r.baseDir, state.Directory.ValueString())
+	stale := []string{}
+	for name := range previous {
+		if _, ok := files[name]; ok && oldDir == dir {
+			continue
+		}
+		stale = append(stale, name)
+	}
+	r.removeFiles(ctx, oldDir, stale, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	{
+		diags := resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *goPackageResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state goPackageResourceModel
+	{
+		diags := req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	managed := map[string]string{}
+	{
+		diags := state.Contents.ElementsAs(ctx, &managed, false)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	names := []string{}
+	for name := range managed {
+		names = append(names, name)
+	}
+
+	dir := filepath.Join(r.baseDir, state.Directory.ValueString())
+	r.removeFiles(ctx, dir, names, &resp.Diagnostics)
+}
+
+// writeFiles writes each rendered file into dir and records their contents in
+// plan.
+func (r *goPackageResource) writeFiles(ctx context.Context, plan *goPackageResourceModel, dir string, files map[string]string, diags *diag.Diagnostics) {
+	for name, contents := range files {
+		ctx := tflog.SetField(ctx, "path", filepath.Join(dir, name))
+		tflog.Debug(ctx, "Writing package file")
+
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), os.ModePerm); err != nil {
+			diags.AddError(
+				"Error writing file",
+				"Unable to write file to disk: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	contents, d := types.MapValueFrom(ctx, types.StringType, files)
+	diags.Append(d...)
+	plan.Contents = contents
+}
+
+// removeFiles deletes the named files from dir, then any directories left
+// empty by their removal up to the provider's base directory. Files that have
+// already been removed are ignored.
+func (r *goPackageResource) removeFiles(ctx context.Context, dir string, names []string, diags *diag.Diagnostics) {
+	if len(names) == 0 {
+		return
+	}
+
+	for _, name := range names {
+		ctx := tflog.SetField(ctx, "path", filepath.Join(dir, name))
+		tflog.Debug(ctx, "Removing package file")
+
+		err := os.Remove(filepath.Join(dir, name))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			diags.AddError(
+				"Error removing file",
+				"Unable to delete file from disk: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	removeEmptyDirs(ctx, dir, r.baseDir, diags)
+}
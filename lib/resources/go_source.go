@@ -2,18 +2,24 @@ package resources
 
 import (
 	"context"
+	"go/parser"
+	"go/token"
 	"os"
 	"path/filepath"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var (
-	_ resource.Resource              = &goSourceResource{}
-	_ resource.ResourceWithConfigure = &goSourceResource{}
+	_ resource.Resource                = &goSourceResource{}
+	_ resource.ResourceWithConfigure   = &goSourceResource{}
+	_ resource.ResourceWithImportState = &goSourceResource{}
+	_ resource.ResourceWithModifyPlan  = &goSourceResource{}
 )
 
 func NewGoSourceResource() resource.Resource {
@@ -43,29 +49,132 @@ func (r *goSourceResource) Metadata(ctx context.Context, req resource.MetadataRe
 
 func (r *goSourceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Description: "Manages a Go source file, rendered from blocks describing its imports and functions.",
 		Attributes: map[string]schema.Attribute{
 			"filename": schema.StringAttribute{
-				Required: true,
+				Required:    true,
+				Description: "The file to write, relative to the provider's base directory. Missing directories are created. Changing it deletes the old file and creates the new one.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"contents": schema.StringAttribute{
-				Optional: true,
-				Computed: true,
+				Optional:    true,
+				Computed:    true,
+				Description: "The rendered file as it exists on-disk.",
+			},
+			"diff": schema.StringAttribute{
+				Computed:    true,
+				Description: "A unified diff from the file on-disk to the rendered content, computed when planning. While the file is up to date, it keeps the diff of the last change.",
 			},
 			"package_name": schema.StringAttribute{
-				Required: true,
+				Required:    true,
+				Description: "The file's package clause.",
+			},
+			"prune_unused_imports": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Drop imports that aren't referenced by any declaration in the file.",
+			},
+			"add_missing_imports": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Add standard library imports for packages that are referenced but not imported.",
+			},
+			"local_import_prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: "Import path prefix of the local module. Matching imports are grouped after third-party imports.",
+			},
+			"definition_json": schema.StringAttribute{
+				Optional:    true,
+				Description: "A JSON document describing further imports and functions, rendered after those from blocks. It mirrors the blocks' structure, and since JSON can nest, call arguments may be arbitrary expressions via \"arg_expression\". Use jsonencode() to build it.",
+			},
+			"verify": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Run go build and go vet on the containing package after writing, restoring the previous content if either fails.",
+			},
+			"overwrite": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Replace an existing file on create even if it wasn't generated by this provider, or has changed since. Generated files start with a header carrying a fingerprint of their content.",
 			},
 		},
 		Blocks: map[string]schema.Block{
 			"import": schema.ListNestedBlock{
 				NestedObject: *ImportSpec,
+				Description:  "An import declaration.",
 			},
 			"func": schema.ListNestedBlock{
 				NestedObject: *FuncDecl,
+				Description:  "A top-level function declaration, rendered in order.",
 			},
 		},
 	}
 }
 
+func (r *goSourceResource) filename(model *goSourceResourceModel) string {
+	return filepath.Join(r.baseDir, model.Filename.ValueString())
+}
+
+// ModifyPlan renders the planned file and diffs it against the file on-disk,
+// so that plans show a readable line diff rather than the whole of contents.
+func (r *goSourceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing is rendered when destroying, or while the configuration
+	// depends on values known only after apply.
+	if req.Plan.Raw.IsNull() || !req.Config.Raw.IsFullyKnown() {
+		return
+	}
+
+	var plan goSourceResourceModel
+	{
+		diags := req.Plan.Get(ctx, &plan)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	contents := renderOwnedGoSource(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	snapshot := takeSnapshot(r.filename(&plan), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diff := unifiedDiff(plan.Filename.ValueString(), snapshot, contents, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if diff == "" && !req.State.Raw.IsNull() {
+		// The file is up to date, so keep the diff of the last apply rather
+		// than planning a change to it alone.
+		var state goSourceResourceModel
+		{
+			diags := req.State.Get(ctx, &state)
+			resp.Diagnostics.Append(diags...)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		plan.Diff = state.Diff
+	} else {
+		plan.Diff = types.StringValue(diff)
+	}
+
+	// Contents must match what apply writes, including when the file has
+	// changed on-disk since the last apply.
+	plan.Contents = types.StringValue(contents)
+
+	{
+		diags := resp.Plan.Set(ctx, &plan)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
 func (r *goSourceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan goSourceResourceModel
 	{
@@ -76,7 +185,7 @@ func (r *goSourceResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	path := filepath.Join(r.baseDir, plan.Filename.ValueString())
+	path := r.filename(&plan)
 	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating directories",
@@ -85,13 +194,31 @@ func (r *goSourceResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	contents := renderGoSource(ctx, &plan, &resp.Diagnostics)
+	contents := renderOwnedGoSource(ctx, &plan, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	plan.Contents = types.StringValue(contents)
 
+	snapshot := takeSnapshot(path, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	checkOverwrite(plan.Filename.ValueString(), snapshot, plan.Overwrite.ValueBool(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The diff is only unknown when planning couldn't render the file.
+	if plan.Diff.IsUnknown() {
+		plan.Diff = types.StringValue(unifiedDiff(plan.Filename.ValueString(), snapshot, contents, &resp.Diagnostics))
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	if err := os.WriteFile(path, []byte(contents), os.ModePerm); err != nil {
 		resp.Diagnostics.AddError(
 			"Error writing file",
@@ -100,6 +227,14 @@ func (r *goSourceResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
+	if plan.Verify.ValueBool() {
+		verifyGoSource(ctx, path, &plan, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			snapshot.restore(ctx, r.baseDir, &resp.Diagnostics)
+			return
+		}
+	}
+
 	{
 		diags := resp.State.Set(ctx, plan)
 		resp.Diagnostics.Append(diags...)
@@ -119,7 +254,8 @@ func (r *goSourceResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	contents, err := os.ReadFile(state.Filename.ValueString())
+	filename := r.filename(&state)
+	contents, err := os.ReadFile(filename)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading file",
@@ -129,6 +265,20 @@ func (r *goSourceResource) Read(ctx context.Context, req resource.ReadRequest, r
 	}
 
 	state.Contents = types.StringValue(string(contents))
+
+	// Imported resources only know their filename, so take the package name
+	// from the file itself.
+	if state.PackageName.IsNull() {
+		f, err := parser.ParseFile(token.NewFileSet(), filename, contents, parser.PackageClauseOnly)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error reading file",
+				"Unable to parse package clause: "+err.Error(),
+			)
+			return
+		}
+		state.PackageName = types.StringValue(f.Name.Name)
+	}
 	{
 		diags := resp.State.Set(ctx, &state)
 		resp.Diagnostics.Append(diags...)
@@ -148,15 +298,28 @@ func (r *goSourceResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
-	path := filepath.Join(r.baseDir, plan.Filename.ValueString())
+	path := r.filename(&plan)
 
-	contents := renderGoSource(ctx, &plan, &resp.Diagnostics)
+	contents := renderOwnedGoSource(ctx, &plan, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	plan.Contents = types.StringValue(contents)
 
+	snapshot := takeSnapshot(path, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The diff is only unknown when planning couldn't render the file.
+	if plan.Diff.IsUnknown() {
+		plan.Diff = types.StringValue(unifiedDiff(plan.Filename.ValueString(), snapshot, contents, &resp.Diagnostics))
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	if err := os.WriteFile(path, []byte(contents), os.ModePerm); err != nil {
 		resp.Diagnostics.AddError(
 			"Error writing file",
@@ -165,6 +328,14 @@ func (r *goSourceResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
+	if plan.Verify.ValueBool() {
+		verifyGoSource(ctx, path, &plan, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			snapshot.restore(ctx, r.baseDir, &resp.Diagnostics)
+			return
+		}
+	}
+
 	{
 		diags := resp.State.Set(ctx, plan)
 		resp.Diagnostics.Append(diags...)
@@ -184,8 +355,24 @@ func (r *goSourceResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 
+	filename := r.filename(&state)
+
+	// Only remove the file if it's still as this provider wrote it.
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading file",
+			"Unable to read file before deleting: "+err.Error(),
+		)
+		return
+	}
+	checkRemove(state.Filename.ValueString(), contents, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Remove the file
-	if err := os.Remove(state.Filename.ValueString()); err != nil {
+	if err := os.Remove(filename); err != nil {
 		resp.Diagnostics.AddError(
 			"Error removing file",
 			"Unable to delete file from disk: "+err.Error(),
@@ -194,31 +381,12 @@ func (r *goSourceResource) Delete(ctx context.Context, req resource.DeleteReques
 	}
 
 	// Then remove any empty directories above it in the filesystem.
-	dir := filepath.Dir(state.Filename.ValueString())
-	for dir != "/" {
-		ctx = tflog.SetField(ctx, "dir", dir)
-		tflog.Debug(ctx, "empty-dir removal loop")
-		entries, err := os.ReadDir(dir)
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Error searching for empty directories to remove",
-				"Unable to find empty directories to remove after file deletion: "+err.Error(),
-			)
-			return
-		}
-
-		if len(entries) > 0 {
-			break
-		}
-
-		if err := os.Remove(dir); err != nil {
-			resp.Diagnostics.AddError(
-				"Error removing empty directory",
-				"Unable to remove empty directory after file deletion: "+err.Error(),
-			)
-			return
-		}
+	removeEmptyDirs(ctx, filepath.Dir(filename), r.baseDir, &resp.Diagnostics)
+}
 
-		dir = filepath.Dir(dir)
-	}
-}
\ No newline at end of file
+// ImportState adopts an existing Go source file, identified by its filename
+// relative to the provider's base directory. Its declarations aren't read
+// back into blocks, so the next apply rewrites it from configuration.
+func (r *goSourceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("filename"), req, resp)
+}
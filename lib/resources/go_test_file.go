@@ -0,0 +1,300 @@
+package resources
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource               = &goTestResource{}
+	_ resource.ResourceWithConfigure  = &goTestResource{}
+	_ resource.ResourceWithModifyPlan = &goTestResource{}
+)
+
+func NewGoTestResource() resource.Resource {
+	return &goTestResource{}
+}
+
+type goTestResource struct {
+	baseDir string
+}
+
+func (r *goTestResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	rd, ok := req.ProviderData.(*ResourceData)
+	if !ok {
+		return
+	}
+
+	r.baseDir = rd.BaseDir
+}
+
+func (r *goTestResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_go_test"
+}
+
+func (r *goTestResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a _test.go file of tests, benchmarks, fuzz tests, and examples.",
+		Attributes: map[string]schema.Attribute{
+			"filename": schema.StringAttribute{
+				Required:    true,
+				Description: "The test file to write, relative to the provider's base directory. Must end in _test.go.",
+			},
+			"contents": schema.StringAttribute{
+				Computed:    true,
+				Description: "The rendered test file as it exists on-disk.",
+			},
+			"package_name": schema.StringAttribute{
+				Required:    true,
+				Description: "The package under test.",
+			},
+			"external": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Declare the file in the external test package, package_name with a _test suffix, so it can only use the package's exported API.",
+			},
+			"add_missing_imports": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Add standard library imports for packages that are referenced but not imported.",
+			},
+			"local_import_prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: "Import path prefix of the local module. Matching imports are grouped after third-party imports.",
+			},
+			"overwrite": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Replace an existing file on create or rename even if it wasn't generated by this provider, or has changed since. Generated files start with a header carrying a fingerprint of their content.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"import": schema.ListNestedBlock{
+				NestedObject: *ImportSpec,
+				Description:  "An import declaration. The testing and reflect packages are imported whenever generated code uses them.",
+			},
+			"test": schema.ListNestedBlock{
+				NestedObject: *TestFunc,
+				Description:  "A test function, taking a *testing.T.",
+			},
+			"benchmark": schema.ListNestedBlock{
+				NestedObject: *TestFunc,
+				Description:  "A benchmark function, taking a *testing.B.",
+			},
+			"fuzz": schema.ListNestedBlock{
+				NestedObject: *TestFunc,
+				Description:  "A fuzz test, taking a *testing.F.",
+			},
+			"example": schema.ListNestedBlock{
+				NestedObject: *Example,
+				Description:  "An example function, optionally checked against its expected output.",
+			},
+			"table_test": schema.ListNestedBlock{
+				NestedObject: *TableTest,
+				Description:  "A table-driven test calling a function with each case's arguments and comparing its results.",
+			},
+		},
+	}
+}
+
+func (r *goTestResource) filename(model *goTestResourceModel) string {
+	return filepath.Join(r.baseDir, model.Filename.ValueString())
+}
+
+// ModifyPlan renders the planned file, so that contents shows a change
+// whenever the file on-disk no longer matches the configuration.
+func (r *goTestResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing is rendered when destroying, or while the configuration
+	// depends on values known only after apply.
+	if req.Plan.Raw.IsNull() || !req.Config.Raw.IsFullyKnown() {
+		return
+	}
+
+	var plan goTestResourceModel
+	{
+		diags := req.Plan.Get(ctx, &plan)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Contents = types.StringValue(renderOwnedGoTest(ctx, &plan, &resp.Diagnostics))
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	{
+		diags := resp.Plan.Set(ctx, &plan)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *goTestResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan goTestResourceModel
+	{
+		diags := req.Plan.Get(ctx, &plan)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(ctx, &plan, true, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	{
+		diags := resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *goTestResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state goTestResourceModel
+	{
+		diags := req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	contents, err := os.ReadFile(r.filename(&state))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading file",
+			"Unable to read file from disk: "+err.Error(),
+		)
+		return
+	}
+
+	state.Contents = types.StringValue(string(contents))
+	{
+		diags := resp.State.Set(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *goTestResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state goTestResourceModel
+	{
+		diags := req.Plan.Get(ctx, &plan)
+		resp.Diagnostics.Append(diags...)
+		diags = req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A renamed file must not replace one this provider doesn't own, but
+	// otherwise the file is rewritten even if it changed on-disk.
+	renamed := r.filename(&state) != r.filename(&plan)
+	r.write(ctx, &plan, renamed, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// If the file was renamed, clean up after its old location.
+	if renamed {
+		r.remove(ctx, &state, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	{
+		diags := resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *goTestResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state goTestResourceModel
+	{
+		diags := req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.remove(ctx, &state, &resp.Diagnostics)
+}
+
+// write renders model and writes it to its file. If check is set, an
+// existing file is only replaced if this provider generated it, or overwrite
+// is set.
+func (r *goTestResource) write(ctx context.Context, model *goTestResourceModel, check bool, diags *diag.Diagnostics) {
+	contents := renderOwnedGoTest(ctx, model, diags)
+	if diags.HasError() {
+		return
+	}
+
+	path := r.filename(model)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		diags.AddError(
+			"Error creating directories",
+			"Unable to create directory to hold new file: "+err.Error(),
+		)
+		return
+	}
+
+	if check {
+		snapshot := takeSnapshot(path, diags)
+		if diags.HasError() {
+			return
+		}
+		checkOverwrite(model.Filename.ValueString(), snapshot, model.Overwrite.ValueBool(), diags)
+		if diags.HasError() {
+			return
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(contents), os.ModePerm); err != nil {
+		diags.AddError(
+			"Error writing file",
+			"Unable to write file to disk: "+err.Error(),
+		)
+		return
+	}
+
+	model.Contents = types.StringValue(contents)
+}
+
+// remove deletes model's file, if it's still as this provider wrote it, and
+// any directories it leaves empty.
+func (r *goTestResource) remove(ctx context.Context, model *goTestResourceModel, diags *diag.Diagnostics) {
+	filename := r.filename(model)
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		diags.AddError(
+			"Error reading file",
+			"Unable to read file before deleting: "+err.Error(),
+		)
+		return
+	}
+	checkRemove(model.Filename.ValueString(), contents, diags)
+	if diags.HasError() {
+		return
+	}
+
+	if err := os.Remove(filename); err != nil {
+		diags.AddError(
+			"Error removing file",
+			"Unable to delete file from disk: "+err.Error(),
+		)
+		return
+	}
+
+	removeEmptyDirs(ctx, filepath.Dir(filename), r.baseDir, diags)
+}
@@ -0,0 +1,200 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource              = &goTestRunResource{}
+	_ resource.ResourceWithConfigure = &goTestRunResource{}
+)
+
+func NewGoTestRunResource() resource.Resource {
+	return &goTestRunResource{}
+}
+
+// goTestRunResource runs go test whenever it's created or any of its
+// arguments change, failing the apply if the tests do. It doesn't manage
+// anything on disk, so reading and deleting it are no-ops.
+type goTestRunResource struct {
+	baseDir string
+}
+
+func (r *goTestRunResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	rd, ok := req.ProviderData.(*ResourceData)
+	if !ok {
+		return
+	}
+
+	r.baseDir = rd.BaseDir
+}
+
+func (r *goTestRunResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_go_test_run"
+}
+
+func (r *goTestRunResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Runs go test when created or when its triggers change, failing if any test fails.",
+		Attributes: map[string]schema.Attribute{
+			"directory": schema.StringAttribute{
+				Required:    true,
+				Description: "The directory to run go test in, relative to the provider's base directory. Usually the module root.",
+			},
+			"packages": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "The packages to test. Defaults to [\"./...\"].",
+			},
+			"flags": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Extra flags passed to go test, e.g. [\"-race\", \"-count=1\"].",
+			},
+			"env": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Environment variables added to the provider's own, e.g. {GOFLAGS = \"-mod=vendor\"} to test offline.",
+			},
+			"timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: "How long to wait for go test, as a Go duration such as \"5m\". Defaults to no limit beyond go test's own.",
+			},
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Arbitrary values that re-run the tests when they change, such as the contents of the files under test.",
+			},
+			"output": schema.StringAttribute{
+				Computed:    true,
+				Description: "The output of the last successful run.",
+			},
+		},
+	}
+}
+
+func (r *goTestRunResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan goTestRunResourceModel
+	{
+		diags := req.Plan.Get(ctx, &plan)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.run(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	{
+		diags := resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *goTestRunResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+}
+
+func (r *goTestRunResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan goTestRunResourceModel
+	{
+		diags := req.Plan.Get(ctx, &plan)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.run(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	{
+		diags := resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *goTestRunResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+var failedTest = regexp.MustCompile(`(?m)^\s*--- FAIL: (\S+)`)
+
+func (r *goTestRunResource) run(ctx context.Context, model *goTestRunResourceModel, diags *diag.Diagnostics) {
+	if !model.Timeout.IsNull() {
+		timeout, err := time.ParseDuration(model.Timeout.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("timeout"),
+				"Invalid timeout",
+				err.Error(),
+			)
+			return
+		}
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	packages := model.Packages
+	if len(packages) == 0 {
+		packages = []string{"./..."}
+	}
+	args := append([]string{"test"}, model.Flags...)
+	args = append(args, packages...)
+
+	dir := filepath.Join(r.baseDir, model.Directory.ValueString())
+	output, err := runGo(ctx, dir, model.Env, args...)
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		summary := "Tests failed"
+		if ctx.Err() != nil {
+			summary = "Tests timed out"
+		}
+
+		names := []string{}
+		for _, m := range failedTest.FindAllStringSubmatch(output, -1) {
+			names = append(names, m[1])
+		}
+		detail := "go " + strings.Join(args, " ") + ": " + exitErr.Error() + "\n\n"
+		if len(names) > 0 {
+			detail += "Failing tests: " + strings.Join(names, ", ") + "\n\n"
+		}
+
+		diags.AddError(summary, detail+output)
+		return
+	default:
+		diags.AddError(
+			"Unable to run go test",
+			"Make sure a Go toolchain is installed and on the provider's PATH: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "Tests passed", map[string]interface{}{"packages": packages})
+	model.Output = types.StringValue(output)
+}
@@ -0,0 +1,274 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource              = &goWorkspaceResource{}
+	_ resource.ResourceWithConfigure = &goWorkspaceResource{}
+)
+
+func NewGoWorkspaceResource() resource.Resource {
+	return &goWorkspaceResource{}
+}
+
+type goWorkspaceResource struct {
+	baseDir string
+}
+
+func (r *goWorkspaceResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	rd, ok := req.ProviderData.(*ResourceData)
+	if !ok {
+		return
+	}
+
+	r.baseDir = rd.BaseDir
+}
+
+func (r *goWorkspaceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_go_workspace"
+}
+
+func (r *goWorkspaceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a go.work file.",
+		Attributes: map[string]schema.Attribute{
+			"directory": schema.StringAttribute{
+				Required:    true,
+				Description: "The workspace's root directory, relative to the provider's base directory.",
+			},
+			"go_version": schema.StringAttribute{
+				Optional:    true,
+				Description: "The Go language version declared by the go directive, e.g. \"1.21\".",
+			},
+			"contents": schema.StringAttribute{
+				Computed:    true,
+				Description: "The rendered go.work as it exists on-disk.",
+			},
+			"overwrite": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Replace an existing go.work on create or when moving the workspace even if it wasn't generated by this provider, or has changed since. Generated files start with a header carrying a fingerprint of their content.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"use": schema.ListNestedBlock{
+				NestedObject: *Use,
+				Description:  "A use directive, adding a module to the workspace.",
+			},
+			"replace": schema.ListNestedBlock{
+				NestedObject: *Replace,
+				Description:  "A replace directive, applying to every module in the workspace.",
+			},
+		},
+	}
+}
+
+func (r *goWorkspaceResource) filename(model *goWorkspaceResourceModel) string {
+	return filepath.Join(r.baseDir, relWorkspaceFilename(model))
+}
+
+// relWorkspaceFilename is model's go.work relative to the provider's base
+// directory, which its fingerprint covers.
+func relWorkspaceFilename(model *goWorkspaceResourceModel) string {
+	return filepath.ToSlash(filepath.Join(model.Directory.ValueString(), "go.work"))
+}
+
+// render renders model as go.work, with the generated-code header.
+func (r *goWorkspaceResource) render(model *goWorkspaceResourceModel, diags *diag.Diagnostics) string {
+	contents := renderGoWorkspace(filepath.Join(r.baseDir, model.Directory.ValueString()), model, diags)
+	if diags.HasError() {
+		return ""
+	}
+	return withOwnershipHeader(relWorkspaceFilename(model), contents)
+}
+
+func (r *goWorkspaceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan goWorkspaceResourceModel
+	{
+		diags := req.Plan.Get(ctx, &plan)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(&plan, true, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	{
+		diags := resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *goWorkspaceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state goWorkspaceResourceModel
+	{
+		diags := req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	path := r.filename(&state)
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// The file was deleted outside of Terraform, so plan to recreate it.
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading file",
+			"Unable to read go.work from disk: "+err.Error(),
+		)
+		return
+	}
+
+	prior := state
+	parseGoWorkspace(path, contents, &state, &prior, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	{
+		diags := resp.State.Set(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *goWorkspaceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state goWorkspaceResourceModel
+	{
+		diags := req.Plan.Get(ctx, &plan)
+		resp.Diagnostics.Append(diags...)
+		diags = req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A moved go.work must not replace one this provider doesn't own, but
+	// otherwise the file is rewritten even if it changed on-disk.
+	moved := r.filename(&state) != r.filename(&plan)
+	r.write(&plan, moved, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// If the workspace directory changed, remove the go.work left in the old
+	// one.
+	if moved {
+		r.remove(ctx, &state, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	{
+		diags := resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *goWorkspaceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state goWorkspaceResourceModel
+	{
+		diags := req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.remove(ctx, &state, &resp.Diagnostics)
+}
+
+// write renders model and writes it to go.work. If check is set, an existing
+// go.work is only replaced if this provider generated it, or overwrite is
+// set.
+func (r *goWorkspaceResource) write(model *goWorkspaceResourceModel, check bool, diags *diag.Diagnostics) {
+	contents := r.render(model, diags)
+	if diags.HasError() {
+		return
+	}
+
+	path := r.filename(model)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		diags.AddError(
+			"Error creating directories",
+			"Unable to create directory to hold go.work: "+err.Error(),
+		)
+		return
+	}
+
+	if check {
+		snapshot := takeSnapshot(path, diags)
+		if diags.HasError() {
+			return
+		}
+		checkOverwrite(relWorkspaceFilename(model), snapshot, model.Overwrite.ValueBool(), diags)
+		if diags.HasError() {
+			return
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(contents), os.ModePerm); err != nil {
+		diags.AddError(
+			"Error writing file",
+			"Unable to write go.work to disk: "+err.Error(),
+		)
+		return
+	}
+
+	model.Contents = types.StringValue(contents)
+}
+
+// remove deletes model's go.work, if it's still as this provider wrote it,
+// and any directories it leaves empty. A go.work that's already gone is
+// left that way.
+func (r *goWorkspaceResource) remove(ctx context.Context, model *goWorkspaceResourceModel, diags *diag.Diagnostics) {
+	filename := r.filename(model)
+	contents, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		diags.AddError(
+			"Error reading file",
+			"Unable to read go.work before deleting: "+err.Error(),
+		)
+		return
+	}
+	checkRemove(relWorkspaceFilename(model), contents, diags)
+	if diags.HasError() {
+		return
+	}
+
+	if err := os.Remove(filename); err != nil {
+		diags.AddError(
+			"Error removing file",
+			"Unable to delete go.work from disk: "+err.Error(),
+		)
+		return
+	}
+
+	removeEmptyDirs(ctx, filepath.Dir(filename), r.baseDir, diags)
+}
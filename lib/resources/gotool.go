@@ -0,0 +1,41 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// runGo runs the locally installed go command in dir, with env added to the
+// provider's own environment, and returns its combined output. A non-nil
+// error means either go couldn't be started or it exited unsuccessfully;
+// callers can tell the two apart with errors.As and *exec.ExitError.
+func runGo(ctx context.Context, dir string, env map[string]string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+
+	cmd.Env = os.Environ()
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		cmd.Env = append(cmd.Env, k+"="+env[k])
+	}
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	ctx = tflog.SetField(ctx, "dir", dir)
+	ctx = tflog.SetField(ctx, "args", args)
+	tflog.Debug(ctx, "Running go command")
+
+	err := cmd.Run()
+	return out.String(), err
+}
@@ -1,57 +1,137 @@
 package resources
 
 import (
-	"context"
-	"fmt"
-	"go/ast"
-	"go/token"
-	"terraform-provider-caiac/lib/astutil"
+	"terraform-provider-caiac/lib/gen"
 
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 type goSourceResourceModel struct {
-	Filename    types.String `tfsdk:"filename"`
-	Contents    types.String `tfsdk:"contents"`
+	Filename           types.String `tfsdk:"filename"`
+	Contents           types.String `tfsdk:"contents"`
+	Diff               types.String `tfsdk:"diff"`
+	PackageName        types.String `tfsdk:"package_name"`
+	PruneUnusedImports types.Bool   `tfsdk:"prune_unused_imports"`
+	AddMissingImports  types.Bool   `tfsdk:"add_missing_imports"`
+	LocalImportPrefix  types.String `tfsdk:"local_import_prefix"`
+	Verify             types.Bool   `tfsdk:"verify"`
+	Overwrite          types.Bool   `tfsdk:"overwrite"`
+	DefinitionJSON     types.String `tfsdk:"definition_json"`
+	Imports            []TImport    `tfsdk:"import"`
+	Funcs              []TFunc      `tfsdk:"func"`
+}
+
+func (m *goSourceResourceModel) toGenFile() *gen.File {
+	return &gen.File{
+		Package:            m.PackageName.ValueString(),
+		Imports:            m.Imports,
+		Funcs:              m.Funcs,
+		PruneUnusedImports: m.PruneUnusedImports.ValueBool(),
+		AddMissingImports:  m.AddMissingImports.ValueBool(),
+		LocalImportPrefix:  m.LocalImportPrefix.ValueString(),
+	}
+}
+
+type goPackageResourceModel struct {
+	Directory   types.String `tfsdk:"directory"`
 	PackageName types.String `tfsdk:"package_name"`
-	Imports     []TImport    `tfsdk:"import"`
-	Funcs       []TFunc      `tfsdk:"func"`
+	Doc         types.String `tfsdk:"doc"`
+	Contents    types.Map    `tfsdk:"contents"`
+	Files       []TFile      `tfsdk:"file"`
 }
 
-var ImportSpec = &schema.NestedBlockObject{
+var File = &schema.NestedBlockObject{
 	Attributes: map[string]schema.Attribute{
 		"name": schema.StringAttribute{
-			Optional: true,
+			Required:    true,
+			Description: "The file's name within the package directory, e.g. \"handlers.go\".",
 		},
-		"path": schema.StringAttribute{
-			Required: true,
+		"package_name": schema.StringAttribute{
+			Optional:    true,
+			Description: "The file's package clause. Defaults to, and must match, the package's name.",
+		},
+		"prune_unused_imports": schema.BoolAttribute{
+			Optional:    true,
+			Description: "Drop imports that aren't referenced by any declaration in the file.",
+		},
+		"add_missing_imports": schema.BoolAttribute{
+			Optional:    true,
+			Description: "Add standard library imports for packages that are referenced but not imported.",
+		},
+		"local_import_prefix": schema.StringAttribute{
+			Optional:    true,
+			Description: "Import path prefix of the local module. Matching imports are grouped after third-party imports.",
+		},
+	},
+	Blocks: map[string]schema.Block{
+		"import": schema.ListNestedBlock{
+			NestedObject: *ImportSpec,
+			Description:  "An import declaration.",
+		},
+		"func": schema.ListNestedBlock{
+			NestedObject: *FuncDecl,
+			Description:  "A top-level function declaration.",
 		},
 	},
 }
 
-type TImport struct {
-	Name *string `tfsdk:"name"`
-	Path string  `tfsdk:"path"`
+type TFile struct {
+	Name               string       `tfsdk:"name"`
+	PackageName        *string      `tfsdk:"package_name"`
+	PruneUnusedImports types.Bool   `tfsdk:"prune_unused_imports"`
+	AddMissingImports  types.Bool   `tfsdk:"add_missing_imports"`
+	LocalImportPrefix  types.String `tfsdk:"local_import_prefix"`
+	Imports            []TImport    `tfsdk:"import"`
+	Funcs              []TFunc      `tfsdk:"func"`
 }
 
-func (i *TImport) toAst() *ast.ImportSpec {
-	tflog.Debug(context.Background(), "OH HEY", nil)
-	return &ast.ImportSpec{
-		Name: astutil.MaybeNewIdent(i.Name),
-		Path: astutil.NewStringLiteral(i.Path),
+// toSourceModel adapts a file within a package to the single-file model, so
+// it can be rendered with renderGoSource.
+func (f *TFile) toSourceModel(pkg string) *goSourceResourceModel {
+	if f.PackageName != nil {
+		pkg = *f.PackageName
+	}
+
+	return &goSourceResourceModel{
+		Filename:           types.StringValue(f.Name),
+		PackageName:        types.StringValue(pkg),
+		PruneUnusedImports: f.PruneUnusedImports,
+		AddMissingImports:  f.AddMissingImports,
+		LocalImportPrefix:  f.LocalImportPrefix,
+		Imports:            f.Imports,
+		Funcs:              f.Funcs,
 	}
 }
 
+var ImportSpec = &schema.NestedBlockObject{
+	Attributes: map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Optional:    true,
+			Description: "The name the package is imported as, or \"_\" or \".\". Defaults to the package's own name.",
+		},
+		"path": schema.StringAttribute{
+			Required:    true,
+			Description: "The imported package's path, e.g. \"fmt\".",
+		},
+	},
+}
+
+// The types describing a file's declarations live in package gen, so they can
+// be used without Terraform. They're aliased here alongside their schemas.
+
+type TImport = gen.Import
+
 var FuncDecl = &schema.NestedBlockObject{
 	Attributes: map[string]schema.Attribute{
 		"name": schema.StringAttribute{
-			Required: true,
+			Required:    true,
+			Description: "The function's name.",
 		},
 	},
 	Blocks: map[string]schema.Block{
 		"signature": schema.SingleNestedBlock{
+			Description: "The function's parameters and results. Omit for a function taking and returning nothing.",
 			Blocks: map[string]schema.Block{
 				"param":  Params,
 				"result": Results,
@@ -63,102 +143,46 @@ var FuncDecl = &schema.NestedBlockObject{
 
 var Params = schema.ListNestedBlock{
 	NestedObject: *Field,
+	Description:  "A parameter, in order.",
 }
 var Results = schema.ListNestedBlock{
 	NestedObject: *Field,
+	Description:  "A result, in order.",
 }
 var Field = &schema.NestedBlockObject{
 	Attributes: map[string]schema.Attribute{
 		"name": schema.StringAttribute{
-			Optional: true,
+			Optional:    true,
+			Description: "The field's name. Omit for an unnamed parameter or result. A function's parameters must be all named or all unnamed, and likewise its results.",
 		},
 		"type": schema.StringAttribute{
-			Optional: true,
+			Optional:    true,
+			Description: "The field's type as Go source, e.g. \"string\" or \"*testing.T\".",
 		},
 	},
 }
 
-type TField struct {
-	Name *string `tfsdk:"name"`
-	Type *string `tfsdk:"type"`
-}
+type TField = gen.Field
 
-func (f *TField) toAst() *ast.Field {
-	names := []*ast.Ident{}
-	name := astutil.MaybeNewIdent(f.Name)
-	if name != nil {
-		names = append(names, name)
-	}
-	return &ast.Field{
-		Names: names,
-		Type:  astutil.MaybeNewIdent(f.Type),
-	}
-}
-
-type TSignature struct {
-	Params  []TField `tfsdk:"param"`
-	Results []TField `tfsdk:"result"`
-}
-
-func (s *TSignature) toAst() *ast.FuncType {
-	if s == nil {
-		return nil
-	}
-
-	params := []*ast.Field{}
-	for _, param := range s.Params {
-		params = append(params, param.toAst())
-	}
-
-	results := []*ast.Field{}
-	for _, res := range s.Results {
-		results = append(results, res.toAst())
-	}
-
-	return &ast.FuncType{
-		Params: &ast.FieldList{
-			List: params,
-		},
-		Results: &ast.FieldList{
-			List: results,
-		},
-	}
-}
+type TSignature = gen.Signature
 
 var Body = schema.SingleNestedBlock{
+	Description: "The function's body.",
 	Blocks: map[string]schema.Block{
 		"statement": schema.ListNestedBlock{
 			NestedObject: Statement,
+			Description:  "A statement, in order.",
 		},
 	},
 }
 
-type TBody struct {
-	Statements []TStatement `tfsdk:"statement"`
-}
-
-func (b *TBody) toAst() *ast.BlockStmt {
-	stmts := []ast.Stmt{}
-	for _, stmt := range b.Statements {
-		stmts = append(stmts, stmt.toAst())
-	}
-	return &ast.BlockStmt{
-		List: stmts,
-	}
-}
-
-type stmtKind = string
-
-// Define just enough to get through the Tour of Go
-const (
-	KExpr   stmtKind = "expression"
-	KReturn stmtKind = "return"
-)
+type TBody = gen.Body
 
 var Statement = schema.NestedBlockObject{
 	Attributes: map[string]schema.Attribute{
 		"kind": schema.StringAttribute{
-			Required: true,
+			Required:    true,
+			Description: "One of \"expression\", for an expression evaluated for its side effects, or \"return\", returning the expression if there is one.",
 		},
 	},
 	Blocks: map[string]schema.Block{
@@ -166,38 +190,20 @@ var Statement = schema.NestedBlockObject{
 	},
 }
 
-type TStatement struct {
-	Kind stmtKind     `tfsdk:"kind"`
-	Expr *TExpression `tfsdk:"expression"`
-}
-
-func (s *TStatement) toAst() ast.Stmt {
-	switch s.Kind {
-	case KExpr:
-		return &ast.ExprStmt{X: s.Expr.toAst()}
-	default:
-		return nil
-	}
-}
-
-type exprKind = string
-
-const (
-	KCall       exprKind = "call"
-	KSelector   exprKind = "selector"
-	KLiteral    exprKind = "literal"
-	KIdentifier exprKind = "identifier"
-)
+type TStatement = gen.Statement
 
 var Expression = &schema.SingleNestedBlock{
+	Description: "The statement's expression.",
 	Attributes: map[string]schema.Attribute{
 		"kind": schema.StringAttribute{
-			Required: true,
+			Required:    true,
+			Description: "One of \"call\", \"selector\", \"literal\", or \"identifier\", naming the block that describes the expression.",
 		},
 	},
 	Blocks: map[string]schema.Block{
 		"literal": schema.SingleNestedBlock{
-			Attributes: Literal.Attributes,
+			Description: "A literal value, for kind \"literal\".",
+			Attributes:  Literal.Attributes,
 		},
 		"selector":   Selector,
 		"identifier": Identifier,
@@ -210,142 +216,315 @@ func init() {
 	Expression.Blocks["call"] = Call
 }
 
-type TExpression struct {
-	Kind       exprKind     `tfsdk:"kind"`
-	Selector   *TSelector   `tfsdk:"selector"`
-	Call       *TCall       `tfsdk:"call"`
-	Literal    *TLiteral    `tfsdk:"literal"`
-	Identifier *TIdentifier `tfsdk:"identifier"`
-}
-
-func (e *TExpression) toAst() ast.Expr {
-	fmt.Println("[TEspression.toAst]")
-	switch e.Kind {
-	case KCall:
-		return e.Call.toAst()
-	case KSelector:
-		return e.Selector.toAst()
-	case KLiteral:
-		return e.Literal.toAst()
-	case KIdentifier:
-		return e.Identifier.toAst()
-	default:
-		return nil
-	}
-}
+type TExpression = gen.Expression
 
-type TIdentifier struct {
-	Name string `tfsdk:"name"`
-}
-
-func (i *TIdentifier) toAst() *ast.Ident {
-	return ast.NewIdent(i.Name)
-}
+type TIdentifier = gen.Identifier
 
 var Identifier = &schema.SingleNestedBlock{
+	Description: "A reference to a name in scope, for kind \"identifier\".",
 	Attributes: map[string]schema.Attribute{
 		"name": schema.StringAttribute{
-			Optional: true,
+			Optional:    true,
+			Description: "The name referred to.",
 		},
 	},
 }
 
 var Call = &schema.SingleNestedBlock{
+	Description: "A function call, for kind \"call\".",
 	Blocks: map[string]schema.Block{
-		"func": Selector,
+		"func": schema.SingleNestedBlock{
+			Description: "The function called.",
+			Attributes:  Selector.Attributes,
+		},
 		"arg": schema.ListNestedBlock{
 			NestedObject: Literal,
+			Description:  "An argument, in order.",
 		},
 	},
 }
 
-type TCall struct {
-	Func *TSelector `tfsdk:"func"`
-	Args []TLiteral `tfsdk:"arg"`
-}
-
-func (c *TCall) toAst() *ast.CallExpr {
-	fmt.Println("[TCall.toAst]")
-	args := []ast.Expr{}
-	for _, arg := range c.Args {
-		args = append(args, arg.toAst())
-	}
-	return &ast.CallExpr{
-		Fun:  c.Func.toAst(),
-		Args: args,
-	}
-}
+type TCall = gen.Call
 
 var Selector = schema.SingleNestedBlock{
+	Description: "A qualified name such as fmt.Println, for kind \"selector\".",
 	Attributes: map[string]schema.Attribute{
 		"from": schema.StringAttribute{
-			Optional: true,
+			Optional:    true,
+			Description: "The package or value the name is selected from, e.g. \"fmt\". Omit for a local name.",
 		},
 		"prop": schema.StringAttribute{
-			Optional: true,
+			Optional:    true,
+			Description: "The selected name, e.g. \"Println\".",
 		},
 	},
 }
 
-type TSelector struct {
-	From *string `tfsdk:"from"`
-	Prop string  `tfsdk:"prop"`
+type TSelector = gen.Selector
+
+type TLiteral = gen.Literal
+
+var Literal = schema.NestedBlockObject{
+	Attributes: map[string]schema.Attribute{
+		"kind": schema.StringAttribute{
+			Optional:    true,
+			Description: "One of \"string\", rendered as a quoted and escaped Go string, or \"int\" or \"identifier\", rendered as is.",
+		},
+		"value": schema.StringAttribute{
+			Optional:    true,
+			Description: "The literal's value.",
+		},
+	},
 }
 
-func (s *TSelector) toAst() *ast.SelectorExpr {
-	return &ast.SelectorExpr{
-		X:   astutil.MaybeNewIdent(s.From),
-		Sel: ast.NewIdent(s.Prop),
-	}
+type TFunc = gen.Func
+
+type goModuleResourceModel struct {
+	Directory  types.String `tfsdk:"directory"`
+	ModulePath types.String `tfsdk:"module_path"`
+	GoVersion  types.String `tfsdk:"go_version"`
+	Toolchain  types.String `tfsdk:"toolchain"`
+	Contents   types.String `tfsdk:"contents"`
+	Requires   []TRequire   `tfsdk:"require"`
+	Replaces   []TReplace   `tfsdk:"replace"`
+	Excludes   []TExclude   `tfsdk:"exclude"`
+	Retracts   []TRetract   `tfsdk:"retract"`
 }
 
-type litKind = string
+var Require = &schema.NestedBlockObject{
+	Attributes: map[string]schema.Attribute{
+		"path": schema.StringAttribute{
+			Required:    true,
+			Description: "The required module's path.",
+		},
+		"version": schema.StringAttribute{
+			Required:    true,
+			Description: "The minimum required version of the module.",
+		},
+		"indirect": schema.BoolAttribute{
+			Optional:    true,
+			Description: "Whether the requirement is marked with an \"// indirect\" comment.",
+		},
+	},
+}
 
-const (
-	LitIdent  litKind = "identifier"
-	LitString litKind = "string"
-	LitInt    litKind = "int"
-)
+type TRequire struct {
+	Path     string `tfsdk:"path"`
+	Version  string `tfsdk:"version"`
+	Indirect *bool  `tfsdk:"indirect"`
+}
 
-type TLiteral struct {
-	Kind  litKind `tfsdk:"kind"`
-	Value string  `tfsdk:"value"`
-}
-
-func (l *TLiteral) toAst() *ast.BasicLit {
-	switch l.Kind {
-	case LitIdent:
-		return &ast.BasicLit{Kind: token.IDENT, Value: l.Value}
-	case LitString:
-		return &ast.BasicLit{Kind: token.STRING, Value: `"` + l.Value + `"`}
-	case LitInt:
-		return &ast.BasicLit{Kind: token.INT, Value: l.Value}
-	default:
-		return nil
-	}
+var Replace = &schema.NestedBlockObject{
+	Attributes: map[string]schema.Attribute{
+		"old_path": schema.StringAttribute{
+			Required:    true,
+			Description: "The path of the module being replaced.",
+		},
+		"old_version": schema.StringAttribute{
+			Optional:    true,
+			Description: "The version being replaced. If omitted, all versions are replaced.",
+		},
+		"new_path": schema.StringAttribute{
+			Required:    true,
+			Description: "The replacement module path, or a local directory.",
+		},
+		"new_version": schema.StringAttribute{
+			Optional:    true,
+			Description: "The replacement version. Must be omitted when new_path is a local directory.",
+		},
+	},
 }
 
-var Literal = schema.NestedBlockObject{
+type TReplace struct {
+	OldPath    string  `tfsdk:"old_path"`
+	OldVersion *string `tfsdk:"old_version"`
+	NewPath    string  `tfsdk:"new_path"`
+	NewVersion *string `tfsdk:"new_version"`
+}
+
+var Exclude = &schema.NestedBlockObject{
 	Attributes: map[string]schema.Attribute{
-		"kind": schema.StringAttribute{
-			Optional: true,
+		"path": schema.StringAttribute{
+			Required:    true,
+			Description: "The excluded module's path.",
 		},
-		"value": schema.StringAttribute{
-			Optional: true,
+		"version": schema.StringAttribute{
+			Required:    true,
+			Description: "The excluded version.",
 		},
 	},
 }
 
-type TFunc struct {
-	Name      string      `tfsdk:"name"`
-	Signature *TSignature `tfsdk:"signature"`
-	Body      *TBody      `tfsdk:"body"`
+type TExclude struct {
+	Path    string `tfsdk:"path"`
+	Version string `tfsdk:"version"`
 }
 
-func (f *TFunc) toAst() *ast.FuncDecl {
-	return &ast.FuncDecl{
-		Name: ast.NewIdent(f.Name),
-		Type: f.Signature.toAst(),
-		Body: f.Body.toAst(),
-	}
+var Retract = &schema.NestedBlockObject{
+	Attributes: map[string]schema.Attribute{
+		"low": schema.StringAttribute{
+			Required:    true,
+			Description: "The retracted version, or the lower bound of a retracted range.",
+		},
+		"high": schema.StringAttribute{
+			Optional:    true,
+			Description: "The upper bound of a retracted range. If omitted, only low is retracted.",
+		},
+		"rationale": schema.StringAttribute{
+			Optional:    true,
+			Description: "Why the versions were retracted, rendered as a comment.",
+		},
+	},
+}
+
+type TRetract struct {
+	Low       string  `tfsdk:"low"`
+	High      *string `tfsdk:"high"`
+	Rationale *string `tfsdk:"rationale"`
+}
+
+type goWorkspaceResourceModel struct {
+	Directory types.String `tfsdk:"directory"`
+	GoVersion types.String `tfsdk:"go_version"`
+	Contents  types.String `tfsdk:"contents"`
+	Overwrite types.Bool   `tfsdk:"overwrite"`
+	Uses      []TUse       `tfsdk:"use"`
+	Replaces  []TReplace   `tfsdk:"replace"`
+}
+
+var Use = &schema.NestedBlockObject{
+	Attributes: map[string]schema.Attribute{
+		"path": schema.StringAttribute{
+			Required:    true,
+			Description: "The module's directory, relative to the workspace directory.",
+		},
+		"module_path": schema.StringAttribute{
+			Optional:    true,
+			Description: "The module path expected to be declared by the go.mod in path.",
+		},
+	},
+}
+
+type TUse struct {
+	Path       string  `tfsdk:"path"`
+	ModulePath *string `tfsdk:"module_path"`
+}
+
+type goTestResourceModel struct {
+	Filename          types.String `tfsdk:"filename"`
+	Contents          types.String `tfsdk:"contents"`
+	PackageName       types.String `tfsdk:"package_name"`
+	External          types.Bool   `tfsdk:"external"`
+	AddMissingImports types.Bool   `tfsdk:"add_missing_imports"`
+	LocalImportPrefix types.String `tfsdk:"local_import_prefix"`
+	Overwrite         types.Bool   `tfsdk:"overwrite"`
+	Imports           []TImport    `tfsdk:"import"`
+	Tests             []TTestFunc  `tfsdk:"test"`
+	Benchmarks        []TTestFunc  `tfsdk:"benchmark"`
+	Fuzzes            []TTestFunc  `tfsdk:"fuzz"`
+	Examples          []TExample   `tfsdk:"example"`
+	TableTests        []TTableTest `tfsdk:"table_test"`
+}
+
+var TestFunc = &schema.NestedBlockObject{
+	Attributes: map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Required:    true,
+			Description: "The function's name without its Test, Benchmark, or Fuzz prefix. Must not start with a lowercase letter.",
+		},
+	},
+	Blocks: map[string]schema.Block{
+		"body": Body,
+	},
+}
+
+type TTestFunc struct {
+	Name string `tfsdk:"name"`
+	Body *TBody `tfsdk:"body"`
+}
+
+var Example = &schema.NestedBlockObject{
+	Attributes: map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Optional:    true,
+			Description: "The example's name without its Example prefix, e.g. \"Foo\" or \"Foo_bar\". Omit for a package example.",
+		},
+		"output": schema.StringAttribute{
+			Optional:    true,
+			Description: "The expected standard output, rendered as an \"// Output:\" comment.",
+		},
+	},
+	Blocks: map[string]schema.Block{
+		"body": Body,
+	},
+}
+
+type TExample struct {
+	Name   *string `tfsdk:"name"`
+	Output *string `tfsdk:"output"`
+	Body   *TBody  `tfsdk:"body"`
+}
+
+var TableTest = &schema.NestedBlockObject{
+	Attributes: map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Required:    true,
+			Description: "The test's name without its Test prefix.",
+		},
+		"func": schema.StringAttribute{
+			Required:    true,
+			Description: "The function under test, e.g. \"Add\" or \"strings.ToUpper\".",
+		},
+	},
+	Blocks: map[string]schema.Block{
+		"param":  Params,
+		"result": Results,
+		"case": schema.ListNestedBlock{
+			NestedObject: *TestCase,
+			Description:  "A test case, run as a subtest.",
+		},
+	},
+}
+
+type TTableTest struct {
+	Name    string      `tfsdk:"name"`
+	Func    string      `tfsdk:"func"`
+	Params  []TField    `tfsdk:"param"`
+	Results []TField    `tfsdk:"result"`
+	Cases   []TTestCase `tfsdk:"case"`
+}
+
+var TestCase = &schema.NestedBlockObject{
+	Attributes: map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Required:    true,
+			Description: "The case's name, passed to t.Run.",
+		},
+		"args": schema.ListAttribute{
+			ElementType: types.StringType,
+			Optional:    true,
+			Description: "A Go expression for each parameter.",
+		},
+		"want": schema.ListAttribute{
+			ElementType: types.StringType,
+			Optional:    true,
+			Description: "A Go expression for each expected result.",
+		},
+	},
+}
+
+type TTestCase struct {
+	Name string   `tfsdk:"name"`
+	Args []string `tfsdk:"args"`
+	Want []string `tfsdk:"want"`
+}
+
+type goTestRunResourceModel struct {
+	Directory types.String      `tfsdk:"directory"`
+	Packages  []string          `tfsdk:"packages"`
+	Flags     []string          `tfsdk:"flags"`
+	Env       map[string]string `tfsdk:"env"`
+	Timeout   types.String      `tfsdk:"timeout"`
+	Triggers  map[string]string `tfsdk:"triggers"`
+	Output    types.String      `tfsdk:"output"`
 }
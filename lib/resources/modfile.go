@@ -0,0 +1,377 @@
+package resources
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// renderGoModule renders a go.mod file from model. Directives are emitted in
+// the order they're declared in configuration.
+func renderGoModule(model *goModuleResourceModel, diags *diag.Diagnostics) string {
+	f := new(modfile.File)
+
+	if err := module.CheckImportPath(model.ModulePath.ValueString()); err != nil {
+		diags.AddAttributeError(path.Root("module_path"), "Invalid module path", err.Error())
+	}
+	if err := f.AddModuleStmt(model.ModulePath.ValueString()); err != nil {
+		diags.AddAttributeError(path.Root("module_path"), "Invalid module path", err.Error())
+	}
+
+	if !model.GoVersion.IsNull() {
+		if err := f.AddGoStmt(model.GoVersion.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("go_version"), "Invalid go version", err.Error())
+		}
+	}
+
+	if !model.Toolchain.IsNull() {
+		if err := f.AddToolchainStmt(model.Toolchain.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("toolchain"), "Invalid toolchain", err.Error())
+		}
+	}
+
+	for i, req := range model.Requires {
+		p := path.Root("require").AtListIndex(i)
+		checkModulePath(p.AtName("path"), req.Path, diags)
+		checkModuleVersion(p.AtName("version"), req.Version, diags)
+
+		indirect := req.Indirect != nil && *req.Indirect
+		f.AddNewRequire(req.Path, req.Version, indirect)
+	}
+
+	for i, rep := range model.Replaces {
+		p := path.Root("replace").AtListIndex(i)
+		errs := diags.ErrorsCount()
+		checkModulePath(p.AtName("old_path"), rep.OldPath, diags)
+		if rep.OldVersion != nil {
+			checkModuleVersion(p.AtName("old_version"), *rep.OldVersion, diags)
+		}
+
+		// A replacement is either a directory, which has no version, or a
+		// module at a specific version.
+		if modfile.IsDirectoryPath(rep.NewPath) {
+			if rep.NewVersion != nil {
+				diags.AddAttributeError(p.AtName("new_version"), "Invalid replace directive", fmt.Sprintf("A replacement directory such as %q can't have a version.", rep.NewPath))
+			}
+		} else {
+			checkModulePath(p.AtName("new_path"), rep.NewPath, diags)
+			if rep.NewVersion == nil {
+				diags.AddAttributeError(p.AtName("new_version"), "Invalid replace directive", fmt.Sprintf("A replacement module such as %q needs a version; a directory must start with ./ or ../, or be absolute.", rep.NewPath))
+			} else {
+				checkModuleVersion(p.AtName("new_version"), *rep.NewVersion, diags)
+			}
+		}
+		if diags.ErrorsCount() > errs {
+			continue
+		}
+
+		err := f.AddReplace(rep.OldPath, valueOrEmpty(rep.OldVersion), rep.NewPath, valueOrEmpty(rep.NewVersion))
+		if err != nil {
+			diags.AddAttributeError(path.Root("replace").AtListIndex(i), "Invalid replace directive", err.Error())
+		}
+	}
+
+	for i, ex := range model.Excludes {
+		if err := f.AddExclude(ex.Path, ex.Version); err != nil {
+			diags.AddAttributeError(path.Root("exclude").AtListIndex(i), "Invalid exclude directive", err.Error())
+		}
+	}
+
+	for i, ret := range model.Retracts {
+		interval := modfile.VersionInterval{Low: ret.Low, High: ret.Low}
+		if ret.High != nil {
+			interval.High = *ret.High
+		}
+		if err := f.AddRetract(interval, valueOrEmpty(ret.Rationale)); err != nil {
+			diags.AddAttributeError(path.Root("retract").AtListIndex(i), "Invalid retract directive", err.Error())
+		}
+	}
+
+	if diags.HasError() {
+		return ""
+	}
+
+	f.Cleanup()
+	contents, err := f.Format()
+	if err != nil {
+		diags.AddError(
+			"Error printing go.mod",
+			"Unable to serialize go.mod: "+err.Error(),
+		)
+		return ""
+	}
+
+	// The modfile package validates paths as it parses them, so make sure the
+	// result is something the go command would accept.
+	if _, err := modfile.Parse("go.mod", contents, nil); err != nil {
+		diags.AddError(
+			"Error validating go.mod",
+			"The rendered go.mod is invalid: "+err.Error(),
+		)
+		return ""
+	}
+
+	return string(contents)
+}
+
+// parseGoModule parses an existing go.mod into model. Values that were omitted
+// from prior and are equivalent to their defaults stay null, so reading back
+// a rendered file doesn't produce spurious differences.
+func parseGoModule(filename string, data []byte, model *goModuleResourceModel, prior *goModuleResourceModel, diags *diag.Diagnostics) {
+	f, err := modfile.Parse(filename, data, nil)
+	if err != nil {
+		diags.AddError(
+			"Error parsing go.mod",
+			"Unable to parse "+filename+": "+err.Error(),
+		)
+		return
+	}
+
+	if prior == nil {
+		prior = &goModuleResourceModel{}
+	}
+
+	model.ModulePath = types.StringNull()
+	if f.Module != nil {
+		model.ModulePath = types.StringValue(f.Module.Mod.Path)
+	}
+
+	model.GoVersion = types.StringNull()
+	if f.Go != nil {
+		model.GoVersion = types.StringValue(f.Go.Version)
+	}
+
+	model.Toolchain = types.StringNull()
+	if f.Toolchain != nil {
+		model.Toolchain = types.StringValue(f.Toolchain.Name)
+	}
+
+	model.Contents = types.StringValue(string(data))
+
+	model.Requires = nil
+	for i, req := range f.Require {
+		var priorIndirect *bool
+		if i < len(prior.Requires) {
+			priorIndirect = prior.Requires[i].Indirect
+		}
+		model.Requires = append(model.Requires, TRequire{
+			Path:     req.Mod.Path,
+			Version:  req.Mod.Version,
+			Indirect: optionalBool(req.Indirect, priorIndirect),
+		})
+	}
+
+	model.Replaces = nil
+	for i, rep := range f.Replace {
+		var priorRep TReplace
+		if i < len(prior.Replaces) {
+			priorRep = prior.Replaces[i]
+		}
+		model.Replaces = append(model.Replaces, TReplace{
+			OldPath:    rep.Old.Path,
+			OldVersion: optionalString(rep.Old.Version, "", priorRep.OldVersion),
+			NewPath:    rep.New.Path,
+			NewVersion: optionalString(rep.New.Version, "", priorRep.NewVersion),
+		})
+	}
+
+	model.Excludes = nil
+	for _, ex := range f.Exclude {
+		model.Excludes = append(model.Excludes, TExclude{
+			Path:    ex.Mod.Path,
+			Version: ex.Mod.Version,
+		})
+	}
+
+	model.Retracts = nil
+	for i, ret := range f.Retract {
+		var priorRet TRetract
+		if i < len(prior.Retracts) {
+			priorRet = prior.Retracts[i]
+		}
+		model.Retracts = append(model.Retracts, TRetract{
+			Low:       ret.Low,
+			High:      optionalString(ret.High, ret.Low, priorRet.High),
+			Rationale: optionalString(ret.Rationale, "", priorRet.Rationale),
+		})
+	}
+}
+
+// checkModulePath reports an error at p if modPath isn't a valid module path.
+func checkModulePath(p path.Path, modPath string, diags *diag.Diagnostics) {
+	if err := module.CheckPath(modPath); err != nil {
+		diags.AddAttributeError(p, "Invalid module path", err.Error())
+	}
+}
+
+// checkModuleVersion reports an error at p if version isn't a valid semantic
+// version, such as v1.2.3.
+func checkModuleVersion(p path.Path, version string, diags *diag.Diagnostics) {
+	if !semver.IsValid(version) {
+		diags.AddAttributeError(p, "Invalid module version", fmt.Sprintf("%q is not a valid semantic version, such as v1.2.3.", version))
+	}
+}
+
+func valueOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// optionalString returns nil if v is the attribute's implied value and it was
+// omitted previously, and a pointer to v otherwise.
+func optionalString(v string, implied string, prior *string) *string {
+	if v == implied && prior == nil {
+		return nil
+	}
+	return &v
+}
+
+// optionalBool returns nil if v is false and was omitted previously, and a
+// pointer to v otherwise.
+func optionalBool(v bool, prior *bool) *bool {
+	if !v && prior == nil {
+		return nil
+	}
+	return &v
+}
+
+// renderGoWorkspace renders a go.work file from model. Each used directory
+// must already contain a go.mod, relative to dir, declaring the expected
+// module path if one was given.
+func renderGoWorkspace(dir string, model *goWorkspaceResourceModel, diags *diag.Diagnostics) string {
+	f := &modfile.WorkFile{Syntax: new(modfile.FileSyntax)}
+
+	if !model.GoVersion.IsNull() {
+		if err := f.AddGoStmt(model.GoVersion.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("go_version"), "Invalid go version", err.Error())
+		}
+	}
+
+	for i, use := range model.Uses {
+		p := path.Root("use").AtListIndex(i)
+		modulePath, ok := readModulePath(filepath.Join(dir, use.Path, "go.mod"), p, diags)
+		if !ok {
+			continue
+		}
+		if use.ModulePath != nil && *use.ModulePath != modulePath {
+			diags.AddAttributeError(
+				p.AtName("module_path"),
+				"Mismatched module path",
+				fmt.Sprintf("Expected %q to contain module %q, but its go.mod declares %q.", use.Path, *use.ModulePath, modulePath),
+			)
+			continue
+		}
+		f.AddNewUse(use.Path, modulePath)
+	}
+
+	for i, rep := range model.Replaces {
+		err := f.AddReplace(rep.OldPath, valueOrEmpty(rep.OldVersion), rep.NewPath, valueOrEmpty(rep.NewVersion))
+		if err != nil {
+			diags.AddAttributeError(path.Root("replace").AtListIndex(i), "Invalid replace directive", err.Error())
+		}
+	}
+
+	if diags.HasError() {
+		return ""
+	}
+
+	f.Cleanup()
+	contents := modfile.Format(f.Syntax)
+	if _, err := modfile.ParseWork("go.work", contents, nil); err != nil {
+		diags.AddError(
+			"Error validating go.work",
+			"The rendered go.work is invalid: "+err.Error(),
+		)
+		return ""
+	}
+
+	return string(contents)
+}
+
+// readModulePath returns the module path declared by the go.mod at filename,
+// reporting a diagnostic at p if there isn't one.
+func readModulePath(filename string, p path.Path, diags *diag.Diagnostics) (string, bool) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		diags.AddAttributeError(
+			p.AtName("path"),
+			"Missing module",
+			"Workspace directories must contain a go.mod, managed by caiac_go_module or already on-disk: "+err.Error(),
+		)
+		return "", false
+	}
+
+	modulePath := modfile.ModulePath(data)
+	if modulePath == "" {
+		diags.AddAttributeError(
+			p.AtName("path"),
+			"Missing module",
+			filename+" does not declare a module path.",
+		)
+		return "", false
+	}
+
+	return modulePath, true
+}
+
+// parseGoWorkspace parses an existing go.work into model, using prior in the
+// same way as parseGoModule.
+func parseGoWorkspace(filename string, data []byte, model *goWorkspaceResourceModel, prior *goWorkspaceResourceModel, diags *diag.Diagnostics) {
+	f, err := modfile.ParseWork(filename, data, nil)
+	if err != nil {
+		diags.AddError(
+			"Error parsing go.work",
+			"Unable to parse "+filename+": "+err.Error(),
+		)
+		return
+	}
+
+	if prior == nil {
+		prior = &goWorkspaceResourceModel{}
+	}
+
+	model.GoVersion = types.StringNull()
+	if f.Go != nil {
+		model.GoVersion = types.StringValue(f.Go.Version)
+	}
+
+	model.Contents = types.StringValue(string(data))
+
+	// The module path is only recorded in configuration, so carry it over from
+	// prior for directories that are still in use.
+	expected := map[string]*string{}
+	for _, use := range prior.Uses {
+		expected[use.Path] = use.ModulePath
+	}
+
+	model.Uses = nil
+	for _, use := range f.Use {
+		model.Uses = append(model.Uses, TUse{
+			Path:       use.Path,
+			ModulePath: expected[use.Path],
+		})
+	}
+
+	model.Replaces = nil
+	for i, rep := range f.Replace {
+		var priorRep TReplace
+		if i < len(prior.Replaces) {
+			priorRep = prior.Replaces[i]
+		}
+		model.Replaces = append(model.Replaces, TReplace{
+			OldPath:    rep.Old.Path,
+			OldVersion: optionalString(rep.Old.Version, "", priorRep.OldVersion),
+			NewPath:    rep.New.Path,
+			NewVersion: optionalString(rep.New.Version, "", priorRep.NewVersion),
+		})
+	}
+}
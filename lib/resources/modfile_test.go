@@ -0,0 +1,76 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// TestRenderGoModuleInvalidDirectives checks that malformed require and
+// replace directives are reported against the attribute at fault rather
+// than written to go.mod.
+func TestRenderGoModuleInvalidDirectives(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name     string
+		requires []TRequire
+		replaces []TReplace
+		want     string
+	}{
+		{
+			name:     "valid",
+			requires: []TRequire{{Path: "golang.org/x/text", Version: "v0.14.0"}},
+			replaces: []TReplace{
+				{OldPath: "golang.org/x/text", NewPath: "../text"},
+				{OldPath: "golang.org/x/mod", OldVersion: str("v0.12.0"), NewPath: "example.com/mod", NewVersion: str("v0.12.1")},
+			},
+			want: "\n",
+		},
+		{
+			name:     "require path",
+			requires: []TRequire{{Path: "not a path", Version: "v1.0.0"}},
+			want:     `require[0].path: Error: Invalid module path: malformed module path "not a path": invalid char ' '` + "\n",
+		},
+		{
+			name:     "require version",
+			requires: []TRequire{{Path: "golang.org/x/text", Version: "latest"}},
+			want:     `require[0].version: Error: Invalid module version: "latest" is not a valid semantic version, such as v1.2.3.` + "\n",
+		},
+		{
+			name:     "replace versions",
+			replaces: []TReplace{{OldPath: "golang.org/x/text", OldVersion: str("1.0"), NewPath: "example.com/text", NewVersion: str("1.2.3")}},
+			want: `replace[0].new_version: Error: Invalid module version: "1.2.3" is not a valid semantic version, such as v1.2.3.` + "\n" +
+				`replace[0].old_version: Error: Invalid module version: "1.0" is not a valid semantic version, such as v1.2.3.` + "\n",
+		},
+		{
+			name:     "replace directory with version",
+			replaces: []TReplace{{OldPath: "golang.org/x/text", NewPath: "../text", NewVersion: str("v1.0.0")}},
+			want:     `replace[0].new_version: Error: Invalid replace directive: A replacement directory such as "../text" can't have a version.` + "\n",
+		},
+		{
+			name:     "replace module without version",
+			replaces: []TReplace{{OldPath: "golang.org/x/text", NewPath: "example.com/text"}},
+			want:     `replace[0].new_version: Error: Invalid replace directive: A replacement module such as "example.com/text" needs a version; a directory must start with ./ or ../, or be absolute.` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := goModuleResourceModel{
+				ModulePath: types.StringValue("example.com/greet"),
+				GoVersion:  types.StringNull(),
+				Toolchain:  types.StringNull(),
+				Requires:   tt.requires,
+				Replaces:   tt.replaces,
+			}
+
+			var diags diag.Diagnostics
+			renderGoModule(&model, &diags)
+			if got := formatDiagnostics(diags); got != tt.want {
+				t.Errorf("got diagnostics:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// Files written by caiac_go_source, caiac_go_test, and caiac_go_workspace
+// start with a header marking them as generated, which tools such as linters
+// and editors recognise, and carrying a fingerprint of the file. The fingerprint lets the provider tell its own
+// files, unchanged since it wrote them, from files written by hand.
+const (
+	generatedHeader   = "// Code generated by terraform-provider-caiac. DO NOT EDIT.\n"
+	fingerprintPrefix = "// caiac:fingerprint "
+)
+
+// renderOwnedGoSource renders model as a Go source file, as
+// caiac_go_source writes it, with the generated-code header.
+func renderOwnedGoSource(ctx context.Context, model *goSourceResourceModel, diags *diag.Diagnostics) string {
+	contents := renderGoSource(ctx, model, diags)
+	if diags.HasError() {
+		return ""
+	}
+	return withOwnershipHeader(model.Filename.ValueString(), contents)
+}
+
+// renderOwnedGoTest renders model as a _test.go file, as caiac_go_test
+// writes it, with the generated-code header.
+func renderOwnedGoTest(ctx context.Context, model *goTestResourceModel, diags *diag.Diagnostics) string {
+	contents := renderGoTest(ctx, model, diags)
+	if diags.HasError() {
+		return ""
+	}
+	return withOwnershipHeader(model.Filename.ValueString(), contents)
+}
+
+// withOwnershipHeader prepends the generated-code header to the rendered
+// contents of the named file.
+func withOwnershipHeader(filename string, contents string) string {
+	return generatedHeader + fingerprintPrefix + fingerprint(filename, contents) + "\n\n" + contents
+}
+
+// fingerprint identifies the rendered contents of the named file, so that a
+// file moved elsewhere or edited by hand no longer matches its header.
+func fingerprint(filename string, contents string) string {
+	sum := sha256.Sum256([]byte(filename + "\x00" + contents))
+	return hex.EncodeToString(sum[:16])
+}
+
+// ownershipMarker reports whether contents start with the generated-code
+// header, and if so, whether its fingerprint still matches the rest of the
+// file.
+func ownershipMarker(filename string, contents string) (marked bool, matches bool) {
+	if !strings.HasPrefix(contents, generatedHeader+fingerprintPrefix) {
+		return false, false
+	}
+	rest := strings.TrimPrefix(contents, generatedHeader+fingerprintPrefix)
+	marker, rest, ok := strings.Cut(rest, "\n\n")
+	if !ok {
+		return true, false
+	}
+	return true, marker == fingerprint(filename, rest)
+}
+
+// checkOverwrite refuses to replace an existing file unless it's one this
+// provider generated for the same filename and it hasn't changed since, or
+// overwrite is set. These are the same files Delete is willing to remove.
+func checkOverwrite(filename string, existing *snapshotFile, overwrite bool, diags *diag.Diagnostics) {
+	if !existing.existed || overwrite {
+		return
+	}
+
+	marked, matches := ownershipMarker(filename, string(existing.contents))
+	if matches {
+		return
+	}
+
+	detail := fmt.Sprintf("%s exists and wasn't generated by this provider.", filename)
+	if marked {
+		detail = fmt.Sprintf("%s was generated by this provider, but no longer matches the fingerprint in its header, so it may hold changes made by hand or have been copied from another file.", filename)
+	}
+	diags.AddError(
+		"File already exists",
+		detail+" Set overwrite = true to replace it.",
+	)
+}
+
+// checkRemove refuses to remove a file unless it still matches the
+// fingerprint in its generated-code header, so that changes made by hand
+// aren't lost.
+func checkRemove(filename string, contents []byte, diags *diag.Diagnostics) {
+	if _, matches := ownershipMarker(filename, string(contents)); matches {
+		return
+	}
+	diags.AddError(
+		"File changed outside of Terraform",
+		fmt.Sprintf("%s no longer matches the fingerprint in its generated-code header, so it may hold changes made by hand. Restore it with an apply, or remove it from state with terraform state rm.", filename),
+	)
+}
@@ -2,44 +2,101 @@ package resources
 
 import (
 	"context"
-	"go/ast"
+	"errors"
+	"fmt"
 	"go/format"
-	"go/token"
+	"path/filepath"
 	"strings"
 
+	"terraform-provider-caiac/lib/gen"
+
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 func renderGoSource(ctx context.Context, model *goSourceResourceModel, diags *diag.Diagnostics) string {
-	imports, err := makeImportSpecAstNodes(ctx, model.Imports)
-	if err != nil {
-		diags.AddError(
-			"Error converting HCL to AST",
-			"Unable to convert HCL imports to AST imports: "+err.Error(),
-		)
-		return ""
+	return renderGoSourceAt(ctx, path.Empty(), model, diags)
+}
+
+// renderGoSourceAt renders model as a Go source file, reporting diagnostics
+// relative to base. This allows resources that embed a file's declarations
+// in a nested block to attribute problems to the right block.
+func renderGoSourceAt(ctx context.Context, base path.Path, model *goSourceResourceModel, diags *diag.Diagnostics) string {
+	ctx = withRenderSubsystem(ctx)
+	ctx = tflog.SubsystemSetField(ctx, renderSubsystem, "filename", model.Filename.ValueString())
+
+	file := model.toGenFile()
+	imports, funcs := len(file.Imports), len(file.Funcs)
+	if !model.DefinitionJSON.IsNull() {
+		mergeDefinitionJSON(base.AtName("definition_json"), file, model.DefinitionJSON.ValueString(), diags)
+		if diags.HasError() {
+			return ""
+		}
 	}
 
-	functions, err := makeFuncDecl(ctx, model.Funcs)
+	return renderGenFile(ctx, file, func(p gen.Path) (path.Path, gen.Path) {
+		// Declarations from the JSON definition follow those from blocks.
+		if len(p) >= 2 && (p[0].Name == "import" && p[1].Index >= imports || p[0].Name == "func" && p[1].Index >= funcs) {
+			offset := imports
+			if p[0].Name == "func" {
+				offset = funcs
+			}
+			rest := gen.Root(p[0].Name).AtListIndex(p[1].Index - offset)
+			return base.AtName("definition_json"), append(rest, p[2:]...)
+		}
+		return atPath(base, p), nil
+	}, diags)
+}
+
+// mergeDefinitionJSON appends the declarations in a JSON definition to file.
+// The definition may repeat the resource's settings, but not contradict them.
+func mergeDefinitionJSON(p path.Path, file *gen.File, definition string, diags *diag.Diagnostics) {
+	def, err := gen.ParseJSON([]byte(definition))
 	if err != nil {
-		diags.AddError(
-			"Error converting HCL to AST",
-			"Unable to convert HCL function declarations to AST function declarations: "+err.Error(),
-		)
-		return ""
+		diags.AddAttributeError(p, "Invalid definition JSON", "Unable to decode definition: "+err.Error())
+		return
 	}
 
-	decls := []ast.Decl{imports}
-	decls = append(decls, functions...)
-
-	f := &ast.File{
-		Name:  ast.NewIdent(model.PackageName.ValueString()),
-		Decls: decls,
+	if def.Package != "" && def.Package != file.Package {
+		diags.AddAttributeError(p, "Mismatched package name", fmt.Sprintf("The definition declares package %q, but package_name is %q.", def.Package, file.Package))
+	}
+	if def.LocalImportPrefix != "" && file.LocalImportPrefix != "" && def.LocalImportPrefix != file.LocalImportPrefix {
+		diags.AddAttributeError(p, "Mismatched local import prefix", fmt.Sprintf("The definition's local_import_prefix is %q, but the resource's is %q.", def.LocalImportPrefix, file.LocalImportPrefix))
+	}
+	if file.LocalImportPrefix == "" {
+		file.LocalImportPrefix = def.LocalImportPrefix
 	}
-	fset := token.NewFileSet()
+	file.PruneUnusedImports = file.PruneUnusedImports || def.PruneUnusedImports
+	file.AddMissingImports = file.AddMissingImports || def.AddMissingImports
 
-	contents := new(strings.Builder)
-	if err := format.Node(contents, fset, f); err != nil {
+	file.Imports = append(file.Imports, def.Imports...)
+	file.Funcs = append(file.Funcs, def.Funcs...)
+}
+
+// A locator maps a path within a gen.File to the attribute or block that
+// produced it. For parts of the file decoded from JSON, rest is the path
+// within the JSON document.
+type locator func(p gen.Path) (attr path.Path, rest gen.Path)
+
+// renderGenFile renders file, reporting each of its problems as a diagnostic
+// against the configuration path that locate maps it to.
+func renderGenFile(ctx context.Context, file *gen.File, locate locator, diags *diag.Diagnostics) string {
+	contents, err := gen.Render(withRenderTracer(ctx, locate), file)
+
+	var problems gen.Problems
+	if errors.As(err, &problems) {
+		for _, problem := range problems {
+			attr, rest := locate(problem.Path)
+			detail := problem.Detail
+			if len(rest) > 0 {
+				detail = rest.String() + ": " + detail
+			}
+			diags.AddAttributeError(attr, problem.Summary, detail)
+		}
+		return ""
+	}
+	if err != nil {
 		diags.AddError(
 			"Error printing AST",
 			"Unable to serialize AST to string: "+err.Error(),
@@ -47,25 +104,122 @@ func renderGoSource(ctx context.Context, model *goSourceResourceModel, diags *di
 		return ""
 	}
 
-	return contents.String()
+	return string(contents)
 }
 
-func makeImportSpecAstNodes(ctx context.Context, imports []TImport) (ast.Decl, error) {
-	specs := []ast.Spec{}
+// renderGoPackage renders each file in a package, along with a doc.go holding
+// the package documentation if one was provided. The result maps filenames to
+// their contents.
+func renderGoPackage(ctx context.Context, model *goPackageResourceModel, diags *diag.Diagnostics) map[string]string {
+	pkg := model.PackageName.ValueString()
+	validateGoPackage(model, diags)
+
+	files := map[string]string{}
+	if doc := model.Doc.ValueString(); doc != "" {
+		files[packageDocFilename] = renderPackageDoc(pkg, doc, diags)
+	}
+
+	for i, file := range model.Files {
+		p := path.Root("file").AtListIndex(i)
+		files[file.Name] = renderGoSourceAt(ctx, p, file.toSourceModel(pkg), diags)
+	}
 
-	for _, theImport := range imports {
-		specs = append(specs, theImport.toAst())
+	if diags.HasError() {
+		return nil
 	}
 
-	return &ast.GenDecl{Tok: token.IMPORT, Specs: specs}, nil
+	return files
 }
 
-func makeFuncDecl(ctx context.Context, funcs []TFunc) ([]ast.Decl, error) {
-	decls := []ast.Decl{}
+// packageDocFilename is the file holding a package's documentation, following
+// the convention used throughout the standard library.
+const packageDocFilename = "doc.go"
+
+func renderPackageDoc(pkg string, doc string, diags *diag.Diagnostics) string {
+	src := new(strings.Builder)
+	for _, line := range strings.Split(strings.TrimRight(doc, "\n"), "\n") {
+		if line == "" {
+			src.WriteString("//\n")
+			continue
+		}
+		src.WriteString("// " + line + "\n")
+	}
+	src.WriteString("package " + pkg + "\n")
+
+	contents, err := format.Source([]byte(src.String()))
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("doc"),
+			"Error printing package documentation",
+			"Unable to format package documentation: "+err.Error(),
+		)
+		return ""
+	}
+
+	return string(contents)
+}
 
-	for _, theFunc := range funcs {
-		decls = append(decls, theFunc.toAst())
+// validateGoPackage reports problems that only appear when looking at all of
+// a package's files together: mismatched package clauses, clashing filenames,
+// and top-level identifiers declared in more than one file.
+func validateGoPackage(model *goPackageResourceModel, diags *diag.Diagnostics) {
+	pkg := model.PackageName.ValueString()
+	filenames := map[string]bool{}
+	if model.Doc.ValueString() != "" {
+		filenames[packageDocFilename] = true
 	}
 
-	return decls, nil
+	// Declarations in external test packages live in a separate namespace.
+	declared := map[string]map[string]string{}
+
+	for i, file := range model.Files {
+		p := path.Root("file").AtListIndex(i)
+
+		if file.Name != filepath.Base(file.Name) || filepath.Ext(file.Name) != ".go" {
+			diags.AddAttributeError(
+				p.AtName("name"),
+				"Invalid file name",
+				fmt.Sprintf("%q must be a .go file directly within the package directory.", file.Name),
+			)
+		}
+		if filenames[file.Name] {
+			diags.AddAttributeError(
+				p.AtName("name"),
+				"Duplicate file name",
+				fmt.Sprintf("%q is declared more than once in this package.", file.Name),
+			)
+		}
+		filenames[file.Name] = true
+
+		filePkg := pkg
+		if file.PackageName != nil {
+			filePkg = *file.PackageName
+		}
+		isExternalTest := filePkg == pkg+"_test" && strings.HasSuffix(file.Name, "_test.go")
+		if filePkg != pkg && !isExternalTest {
+			diags.AddAttributeError(
+				p.AtName("package_name"),
+				"Mismatched package name",
+				fmt.Sprintf("File %q declares package %q, but all files in this package must declare package %q.", file.Name, filePkg, pkg),
+			)
+		}
+
+		if declared[filePkg] == nil {
+			declared[filePkg] = map[string]string{}
+		}
+		for j, fn := range file.Funcs {
+			if fn.Name == "init" || fn.Name == "_" {
+				continue
+			}
+			if other, ok := declared[filePkg][fn.Name]; ok {
+				diags.AddAttributeError(
+					p.AtName("func").AtListIndex(j).AtName("name"),
+					"Duplicate top-level identifier",
+					fmt.Sprintf("%q is already declared in %q.", fn.Name, other),
+				)
+				continue
+			}
+			declared[filePkg][fn.Name] = file.Name
+		}
+	}
 }
@@ -0,0 +1,201 @@
+package resources
+
+import (
+	"context"
+	"flag"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"terraform-provider-caiac/lib/gen"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with the current output")
+
+// TestRenderGoSource renders each fixture in testdata/render and compares the
+// result with <fixture>.go.golden, or, if rendering fails, the diagnostics
+// with <fixture>.diags.golden. Fixtures are either Terraform configuration
+// (.tf or .tf.json) holding a single caiac_go_source resource, or a JSON
+// model as accepted by definition_json (.json). Rendered files must parse.
+// Run with -update to rewrite the golden files after an intended change in
+// output.
+func TestRenderGoSource(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "render", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goldens := map[string]bool{}
+	for _, fixture := range fixtures {
+		if strings.HasSuffix(fixture, ".golden") {
+			goldens[fixture] = true
+		}
+	}
+
+	for _, fixture := range fixtures {
+		var name string
+		var render func(t *testing.T, fixture string) (string, diag.Diagnostics)
+		switch {
+		case strings.HasSuffix(fixture, ".golden"):
+			continue
+		case strings.HasSuffix(fixture, ".tf"), strings.HasSuffix(fixture, ".tf.json"):
+			name = strings.TrimSuffix(strings.TrimSuffix(fixture, ".json"), ".tf")
+			render = renderConfigFixture
+		case strings.HasSuffix(fixture, ".json"):
+			name = strings.TrimSuffix(fixture, ".json")
+			render = renderModelFixture
+		default:
+			t.Errorf("%s: unknown fixture type", fixture)
+			continue
+		}
+
+		t.Run(filepath.Base(name), func(t *testing.T) {
+			contents, diags := render(t, fixture)
+
+			golden := name + ".go.golden"
+			got := contents
+			if diags.HasError() {
+				golden = name + ".diags.golden"
+				got = formatDiagnostics(diags)
+			} else if _, err := parser.ParseFile(token.NewFileSet(), golden, got, 0); err != nil {
+				t.Errorf("rendered invalid Go: %s\n%s", err, got)
+			}
+			delete(goldens, golden)
+
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%s; run with -update to create it", err)
+			}
+			if got != string(want) {
+				t.Errorf("output differs from %s; run with -update if the change is intended:\n%s", golden, got)
+			}
+		})
+	}
+
+	if *update {
+		return
+	}
+	for golden := range goldens {
+		t.Errorf("%s doesn't belong to any fixture", golden)
+	}
+}
+
+// renderConfigFixture renders the caiac_go_source resource in a Terraform
+// configuration fixture, decoding it as the render subcommand does.
+func renderConfigFixture(t *testing.T, fixture string) (string, diag.Diagnostics) {
+	files, diags := RenderConfig(context.Background(), []string{fixture})
+	if diags.HasError() {
+		return "", diags
+	}
+	if len(files) != 1 {
+		t.Fatalf("%s: want exactly one caiac_go_source resource, got %d", fixture, len(files))
+	}
+	return files[0].Contents, diags
+}
+
+// renderModelFixture renders a JSON model fixture as a resource's
+// definition_json, taking its package name from the model.
+func renderModelFixture(t *testing.T, fixture string) (string, diag.Diagnostics) {
+	data, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := gen.ParseJSON(data)
+	if err != nil {
+		t.Fatalf("%s: %s", fixture, err)
+	}
+
+	model := &goSourceResourceModel{
+		Filename:       types.StringValue(filepath.Base(fixture)),
+		PackageName:    types.StringValue(file.Package),
+		DefinitionJSON: types.StringValue(string(data)),
+	}
+
+	var diags diag.Diagnostics
+	contents := renderGoSource(context.Background(), model, &diags)
+	return contents, diags
+}
+
+// formatDiagnostics prints diagnostics one per line, sorted so the golden
+// files don't depend on the order problems are found in.
+func formatDiagnostics(diags diag.Diagnostics) string {
+	lines := []string{}
+	for _, d := range diags {
+		line := d.Severity().String() + ": " + d.Summary() + ": " + d.Detail()
+		if d, ok := d.(diag.DiagnosticWithPath); ok {
+			line = d.Path().String() + ": " + line
+		}
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// TestRenderedFileCheckOverwrite checks that rendered files only replace
+// files this provider generated for the same filename, unless overwrite is
+// set either by the resource or by the caller, or the resource doesn't mark
+// the files it generates.
+func TestRenderedFileCheckOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	file := RenderedFile{
+		Address:  "caiac_go_source.main",
+		Filename: "main.go",
+		Contents: withOwnershipHeader("main.go", "package main\n"),
+		owned:    true,
+	}
+
+	tests := []struct {
+		name      string
+		existing  string
+		overwrite bool
+		resource  bool
+		unowned   bool
+		want      string
+	}{
+		{name: "missing"},
+		{name: "generated", existing: file.Contents},
+		{name: "generated elsewhere", existing: withOwnershipHeader("other.go", "package main\n"), want: "File already exists"},
+		{name: "hand-written", existing: "package main\n", want: "File already exists"},
+		{name: "hand-written with overwrite", existing: "package main\n", overwrite: true},
+		{name: "hand-written with resource overwrite", existing: "package main\n", resource: true},
+		{name: "hand-written for a resource without ownership", existing: "package main\n", unowned: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "_")+".go")
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			f := file
+			f.Overwrite = tt.resource
+			f.owned = !tt.unowned
+			diags := f.CheckOverwrite(path, tt.overwrite)
+
+			got := ""
+			for _, d := range diags {
+				got = d.Summary()
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q:\n%s", got, tt.want, formatDiagnostics(diags))
+			}
+		})
+	}
+}
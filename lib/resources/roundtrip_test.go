@@ -0,0 +1,380 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// FuzzRenderGoSourceRoundTrip parses Go source, converts it to the model,
+// renders that with renderGoSource, and checks the result is gofmt-equivalent
+// to the input. Inputs using constructs the model can't describe are skipped.
+// String literals are converted to their values, so the renderer must quote
+// them. The golden files of TestRenderGoSource seed the corpus.
+func FuzzRenderGoSourceRoundTrip(f *testing.F) {
+	f.Add("package main\n\nfunc main() {}\n")
+	f.Add("package p\n\nimport (\n\t\"fmt\"\n\tstdos \"os\"\n)\n\nfunc F(a, b int, s string) (n int, err error) {\n\tfmt.Println(\"hi\", 1, nil)\n\treturn\n}\n\nfunc g() *stdos.File {\n\treturn stdos.Stdout\n}\n")
+	f.Add("package p\n\nimport \"strings\"\n\nfunc f() string {\n\treturn strings.ToUpper(strings.TrimSpace(\"x\"))\n}\n")
+	f.Add("package p\n\nfunc asm(x uint64) uint64\n")
+	f.Add("package p\n\nimport \"fmt\"\n\nfunc f() {\n\tfmt.Println(\"say \\\"hi\\\"\\n\", `C:\\`, \"\\u00e9\")\n}\n")
+
+	goldens, err := filepath.Glob(filepath.Join("testdata", "render", "*.go.golden"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, golden := range goldens {
+		src, err := os.ReadFile(golden)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(string(src))
+	}
+
+	f.Fuzz(func(t *testing.T, src string) {
+		want, ok := canonicalSource(src)
+		if !ok {
+			return
+		}
+		model, ok := modelFromSource(src)
+		if !ok {
+			return
+		}
+
+		var diags diag.Diagnostics
+		rendered := renderGoSource(context.Background(), model, &diags)
+		if diags.HasError() {
+			t.Fatalf("rendering failed for\n%s\n%s", src, formatDiagnostics(diags))
+		}
+
+		got, ok := canonicalSource(rendered)
+		if !ok {
+			t.Fatalf("rendered invalid Go for\n%s\ngot:\n%s", src, rendered)
+		}
+		if got != want {
+			t.Errorf("round trip changed\n%s\ninto\n%s", want, got)
+		}
+	})
+}
+
+// canonicalSource formats src without the positions recorded when parsing
+// it, so that layout, such as blank lines and import grouping, which the
+// model doesn't describe, doesn't matter. Imports are merged and sorted, since
+// they're reordered when rendered, and comments are dropped.
+func canonicalSource(src string) (string, bool) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return "", false
+	}
+
+	// Imports are rendered in a single declaration, if there are any.
+	imports := &ast.GenDecl{Tok: token.IMPORT}
+	decls := []ast.Decl{}
+	for _, decl := range f.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			imports.Specs = append(imports.Specs, gen.Specs...)
+			continue
+		}
+		decls = append(decls, decl)
+	}
+	if len(imports.Specs) > 0 {
+		sort.SliceStable(imports.Specs, func(i, j int) bool {
+			return imports.Specs[i].(*ast.ImportSpec).Path.Value < imports.Specs[j].(*ast.ImportSpec).Path.Value
+		})
+		decls = append([]ast.Decl{imports}, decls...)
+	}
+	f.Decls = decls
+
+	// The model declares one parameter per name, so "a, b int" is the same
+	// as "a int, b int". It holds strings' values rather than their source,
+	// so `a` is the same as "a" and "\x61".
+	ast.Inspect(f, func(n ast.Node) bool {
+		if lit, ok := n.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			if value, err := strconv.Unquote(lit.Value); err == nil {
+				lit.Value = strconv.Quote(value)
+			}
+		}
+		if list, ok := n.(*ast.FieldList); ok {
+			split := []*ast.Field{}
+			for _, field := range list.List {
+				if len(field.Names) <= 1 {
+					split = append(split, field)
+					continue
+				}
+				for _, name := range field.Names {
+					split = append(split, &ast.Field{Names: []*ast.Ident{name}, Type: field.Type})
+				}
+			}
+			list.List = split
+		}
+		return true
+	})
+
+	f.Imports = nil
+	f.Comments = nil
+	clearPositions(reflect.ValueOf(f))
+
+	out := new(bytes.Buffer)
+	if err := format.Node(out, token.NewFileSet(), f); err != nil {
+		return "", false
+	}
+	return out.String(), true
+}
+
+// clearPositions sets every token.Pos reachable from v to token.NoPos.
+func clearPositions(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			clearPositions(v.Elem())
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			clearPositions(v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+			switch {
+			case field.Type() == reflect.TypeOf(token.NoPos):
+				field.Set(reflect.ValueOf(token.NoPos))
+			case field.Type() == reflect.TypeOf(&ast.Scope{}), field.Type() == reflect.TypeOf(&ast.Object{}):
+				// Scopes refer back into the tree; there are no positions to
+				// clear that aren't reachable otherwise.
+			default:
+				clearPositions(field)
+			}
+		}
+	}
+}
+
+// modelFromSource converts Go source to the resource's model, reporting
+// false if it uses anything the model can't describe.
+func modelFromSource(src string) (*goSourceResourceModel, bool) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, false
+	}
+
+	model := &goSourceResourceModel{
+		Filename:    types.StringValue("roundtrip.go"),
+		PackageName: types.StringValue(f.Name.Name),
+	}
+
+	paths := map[string]bool{}
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			if decl.Tok != token.IMPORT {
+				return nil, false
+			}
+			for _, spec := range decl.Specs {
+				imp, ok := importFromAst(spec.(*ast.ImportSpec))
+				// Duplicate imports are dropped when rendered.
+				if !ok || paths[imp.Path] {
+					return nil, false
+				}
+				paths[imp.Path] = true
+				model.Imports = append(model.Imports, imp)
+			}
+		case *ast.FuncDecl:
+			fn, ok := funcFromAst(fset, decl)
+			if !ok {
+				return nil, false
+			}
+			model.Funcs = append(model.Funcs, fn)
+		default:
+			return nil, false
+		}
+	}
+
+	return model, true
+}
+
+func importFromAst(spec *ast.ImportSpec) (TImport, bool) {
+	path, err := strconv.Unquote(spec.Path.Value)
+	if err != nil || path == "" || spec.Path.Value != `"`+path+`"` {
+		return TImport{}, false
+	}
+
+	imp := TImport{Path: path}
+	if spec.Name != nil {
+		if !token.IsIdentifier(spec.Name.Name) {
+			return TImport{}, false
+		}
+		name := spec.Name.Name
+		imp.Name = &name
+	}
+	return imp, true
+}
+
+func funcFromAst(fset *token.FileSet, decl *ast.FuncDecl) (TFunc, bool) {
+	if decl.Recv != nil || decl.Type.TypeParams != nil {
+		return TFunc{}, false
+	}
+
+	fn := TFunc{Name: decl.Name.Name, Signature: &TSignature{}}
+
+	var ok bool
+	if fn.Signature.Params, ok = fieldsFromAst(fset, decl.Type.Params); !ok {
+		return TFunc{}, false
+	}
+	if fn.Signature.Results, ok = fieldsFromAst(fset, decl.Type.Results); !ok {
+		return TFunc{}, false
+	}
+
+	if decl.Body == nil {
+		return fn, true
+	}
+	fn.Body = &TBody{}
+	for _, stmt := range decl.Body.List {
+		s, ok := statementFromAst(stmt)
+		if !ok {
+			return TFunc{}, false
+		}
+		fn.Body.Statements = append(fn.Body.Statements, s)
+	}
+	return fn, true
+}
+
+// fieldsFromAst converts a parameter or result list, splitting fields that
+// declare several names into one field per name.
+func fieldsFromAst(fset *token.FileSet, list *ast.FieldList) ([]TField, bool) {
+	if list == nil {
+		return nil, true
+	}
+
+	fields := []TField{}
+	for _, field := range list.List {
+		typ := new(strings.Builder)
+		if err := printer.Fprint(typ, fset, field.Type); err != nil {
+			return nil, false
+		}
+		t := typ.String()
+
+		if len(field.Names) == 0 {
+			fields = append(fields, TField{Type: &t})
+			continue
+		}
+		for _, name := range field.Names {
+			name := name.Name
+			fields = append(fields, TField{Name: &name, Type: &t})
+		}
+	}
+	return fields, true
+}
+
+func statementFromAst(stmt ast.Stmt) (TStatement, bool) {
+	switch stmt := stmt.(type) {
+	case *ast.ExprStmt:
+		expr, ok := expressionFromAst(stmt.X)
+		return TStatement{Kind: "expression", Expr: expr}, ok
+	case *ast.ReturnStmt:
+		switch len(stmt.Results) {
+		case 0:
+			return TStatement{Kind: "return"}, true
+		case 1:
+			expr, ok := expressionFromAst(stmt.Results[0])
+			return TStatement{Kind: "return", Expr: expr}, ok
+		}
+	}
+	return TStatement{}, false
+}
+
+func expressionFromAst(expr ast.Expr) (*TExpression, bool) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return &TExpression{Kind: "identifier", Identifier: &TIdentifier{Name: expr.Name}}, true
+	case *ast.SelectorExpr:
+		sel, ok := selectorFromAst(expr)
+		return &TExpression{Kind: "selector", Selector: sel}, ok
+	case *ast.BasicLit:
+		lit, ok := literalFromAst(expr)
+		return &TExpression{Kind: "literal", Literal: lit}, ok
+	case *ast.CallExpr:
+		if expr.Ellipsis.IsValid() {
+			return nil, false
+		}
+		call := &TCall{}
+
+		var ok bool
+		switch fun := expr.Fun.(type) {
+		case *ast.Ident:
+			call.Func = &TSelector{Prop: fun.Name}
+		case *ast.SelectorExpr:
+			if call.Func, ok = selectorFromAst(fun); !ok {
+				return nil, false
+			}
+		default:
+			return nil, false
+		}
+
+		// Arguments are literals where possible, as they must be in HCL,
+		// and expressions otherwise, as JSON allows.
+		literals := []TLiteral{}
+		exprs := []TExpression{}
+		for _, arg := range expr.Args {
+			e, ok := expressionFromAst(arg)
+			if !ok {
+				return nil, false
+			}
+			exprs = append(exprs, *e)
+			switch e.Kind {
+			case "literal":
+				literals = append(literals, *e.Literal)
+			case "identifier":
+				literals = append(literals, TLiteral{Kind: "identifier", Value: e.Identifier.Name})
+			}
+		}
+		if len(literals) == len(exprs) {
+			call.Args = literals
+		} else {
+			call.ArgExprs = exprs
+		}
+		return &TExpression{Kind: "call", Call: call}, true
+	}
+	return nil, false
+}
+
+func selectorFromAst(expr *ast.SelectorExpr) (*TSelector, bool) {
+	x, ok := expr.X.(*ast.Ident)
+	if !ok {
+		return nil, false
+	}
+	from := x.Name
+	return &TSelector{From: &from, Prop: expr.Sel.Name}, true
+}
+
+func literalFromAst(lit *ast.BasicLit) (*TLiteral, bool) {
+	switch lit.Kind {
+	case token.STRING:
+		// The model holds a string's value, not its source, so any quoting
+		// renders the same value.
+		value, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return nil, false
+		}
+		return &TLiteral{Kind: "string", Value: value}, true
+	case token.INT:
+		if _, err := strconv.ParseInt(lit.Value, 0, 64); err != nil {
+			return nil, false
+		}
+		return &TLiteral{Kind: "int", Value: lit.Value}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,406 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// RenderedFile is a file rendered from a resource in a Terraform
+// configuration, without involving Terraform itself.
+type RenderedFile struct {
+	// Address is the resource's address, e.g. "caiac_go_source.main".
+	Address string
+	// Filename is relative to the provider's base directory.
+	Filename string
+	Contents string
+	// Overwrite is the resource's overwrite attribute.
+	Overwrite bool
+
+	// owned is set for files that carry the generated-code header, whose
+	// resources refuse to replace files they don't own.
+	owned bool
+}
+
+// CheckOverwrite reports an error if writing f to path would replace a file
+// this provider didn't generate, or one that has changed since, in the same
+// way creating the resource would. overwrite allows it regardless.
+func (f RenderedFile) CheckOverwrite(path string, overwrite bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !f.owned {
+		return diags
+	}
+	snapshot := takeSnapshot(path, &diags)
+	if diags.HasError() {
+		return diags
+	}
+	var d diag.Diagnostics
+	checkOverwrite(f.Filename, snapshot, overwrite || f.Overwrite, &d)
+	for _, diagnostic := range d {
+		diags.Append(withAddress(f.Address, diagnostic))
+	}
+	return diags
+}
+
+// standaloneRenderers render the resources whose files depend only on their
+// configuration, from a plan holding it, keyed by resource type name.
+// Resources such as caiac_go_workspace, which read other files, and
+// caiac_go_test_run, which runs commands, need Terraform.
+var standaloneRenderers = map[string]struct {
+	new    func() resource.Resource
+	render func(ctx context.Context, plan tfsdk.Plan, diags *diag.Diagnostics) []RenderedFile
+}{
+	"caiac_go_source":  {NewGoSourceResource, renderGoSourcePlan},
+	"caiac_go_test":    {NewGoTestResource, renderGoTestPlan},
+	"caiac_go_package": {NewGoPackageResource, renderGoPackagePlan},
+	"caiac_go_module":  {NewGoModuleResource, renderGoModulePlan},
+}
+
+// RenderConfig renders every resource in the given .tf or .tf.json files
+// that can be rendered without Terraform: caiac_go_source, caiac_go_test,
+// caiac_go_package, and caiac_go_module. Blocks are decoded against the
+// resource's own schema, so the configuration is interpreted exactly as it
+// would be by Terraform. Only literal values are supported: expressions
+// referring to variables or other resources can't be evaluated without
+// Terraform.
+func RenderConfig(ctx context.Context, filenames []string) ([]RenderedFile, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	parser := hclparse.NewParser()
+	files := []RenderedFile{}
+
+	for _, filename := range filenames {
+		var file *hcl.File
+		var hclDiags hcl.Diagnostics
+		if filepath.Ext(filename) == ".json" {
+			file, hclDiags = parser.ParseJSONFile(filename)
+		} else {
+			file, hclDiags = parser.ParseHCLFile(filename)
+		}
+		diags.Append(fromHCLDiagnostics(hclDiags)...)
+		if hclDiags.HasErrors() {
+			continue
+		}
+
+		content, _, hclDiags := file.Body.PartialContent(&hcl.BodySchema{
+			Blocks: []hcl.BlockHeaderSchema{{Type: "resource", LabelNames: []string{"type", "name"}}},
+		})
+		diags.Append(fromHCLDiagnostics(hclDiags)...)
+
+		for _, block := range content.Blocks {
+			renderer, ok := standaloneRenderers[block.Labels[0]]
+			if !ok {
+				continue
+			}
+			address := block.Labels[0] + "." + block.Labels[1]
+
+			var d diag.Diagnostics
+			plan := decodeBlock(ctx, block, renderer.new(), &d)
+			var rendered []RenderedFile
+			if !d.HasError() {
+				rendered = renderer.render(ctx, plan, &d)
+			}
+			for _, diagnostic := range d {
+				diags.Append(withAddress(address, diagnostic))
+			}
+			if d.HasError() {
+				continue
+			}
+			for _, f := range rendered {
+				f.Address = address
+				files = append(files, f)
+			}
+		}
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].Address != files[j].Address {
+			return files[i].Address < files[j].Address
+		}
+		return files[i].Filename < files[j].Filename
+	})
+	return files, diags
+}
+
+// decodeBlock decodes a resource block against r's schema, into a plan as
+// Terraform would send it.
+func decodeBlock(ctx context.Context, block *hcl.Block, r resource.Resource, diags *diag.Diagnostics) tfsdk.Plan {
+	var resp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &resp)
+	sch := resp.Schema
+	spec := objectSpec(sch.Attributes, sch.Blocks)
+
+	// Meta-arguments that repeat or configure a resource only make sense
+	// within Terraform.
+	meta, body, hclDiags := block.Body.PartialContent(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{{Name: "count"}, {Name: "for_each"}},
+		Blocks:     []hcl.BlockHeaderSchema{{Type: "lifecycle"}},
+	})
+	diags.Append(fromHCLDiagnostics(hclDiags)...)
+	for name, attr := range meta.Attributes {
+		diags.AddError("Unsupported meta-argument", fmt.Sprintf("%s: %q can't be rendered without Terraform.", attr.NameRange, name))
+	}
+
+	// Other meta-arguments, such as depends_on and provider, are ignored.
+	val, _, hclDiags := hcldec.PartialDecode(body, spec, nil)
+	diags.Append(fromHCLDiagnostics(hclDiags)...)
+	if diags.HasError() {
+		return tfsdk.Plan{}
+	}
+
+	raw, err := ctyToTerraform(val, sch.Type().TerraformType(ctx))
+	if err != nil {
+		diags.AddError("Unable to decode resource", err.Error())
+		return tfsdk.Plan{}
+	}
+	return tfsdk.Plan{Schema: sch, Raw: raw}
+}
+
+func renderGoSourcePlan(ctx context.Context, plan tfsdk.Plan, diags *diag.Diagnostics) []RenderedFile {
+	var model goSourceResourceModel
+	diags.Append(plan.Get(ctx, &model)...)
+	if diags.HasError() {
+		return nil
+	}
+
+	contents := renderOwnedGoSource(ctx, &model, diags)
+	return []RenderedFile{{
+		Filename:  model.Filename.ValueString(),
+		Contents:  contents,
+		Overwrite: model.Overwrite.ValueBool(),
+		owned:     true,
+	}}
+}
+
+func renderGoTestPlan(ctx context.Context, plan tfsdk.Plan, diags *diag.Diagnostics) []RenderedFile {
+	var model goTestResourceModel
+	diags.Append(plan.Get(ctx, &model)...)
+	if diags.HasError() {
+		return nil
+	}
+
+	contents := renderOwnedGoTest(ctx, &model, diags)
+	return []RenderedFile{{
+		Filename:  model.Filename.ValueString(),
+		Contents:  contents,
+		Overwrite: model.Overwrite.ValueBool(),
+		owned:     true,
+	}}
+}
+
+func renderGoPackagePlan(ctx context.Context, plan tfsdk.Plan, diags *diag.Diagnostics) []RenderedFile {
+	var model goPackageResourceModel
+	diags.Append(plan.Get(ctx, &model)...)
+	if diags.HasError() {
+		return nil
+	}
+
+	files := []RenderedFile{}
+	for name, contents := range renderGoPackage(ctx, &model, diags) {
+		files = append(files, RenderedFile{
+			Filename: filepath.Join(model.Directory.ValueString(), name),
+			Contents: contents,
+		})
+	}
+	return files
+}
+
+func renderGoModulePlan(ctx context.Context, plan tfsdk.Plan, diags *diag.Diagnostics) []RenderedFile {
+	var model goModuleResourceModel
+	diags.Append(plan.Get(ctx, &model)...)
+	if diags.HasError() {
+		return nil
+	}
+
+	contents := renderGoModule(&model, diags)
+	return []RenderedFile{{
+		Filename: filepath.Join(model.Directory.ValueString(), "go.mod"),
+		Contents: contents,
+	}}
+}
+
+// objectSpec builds a decoder spec from a resource schema. Computed
+// attributes that can't be configured are left out, and so decode as null.
+func objectSpec(attrs map[string]schema.Attribute, blocks map[string]schema.Block) hcldec.ObjectSpec {
+	spec := hcldec.ObjectSpec{}
+
+	for name, attr := range attrs {
+		if attr.IsComputed() && !attr.IsOptional() {
+			continue
+		}
+		spec[name] = &hcldec.AttrSpec{
+			Name:     name,
+			Type:     ctyType(attr.GetType().TerraformType(context.Background())),
+			Required: attr.IsRequired(),
+		}
+	}
+
+	for name, block := range blocks {
+		// Some shared blocks are declared as pointers.
+		if single, ok := block.(*schema.SingleNestedBlock); ok {
+			block = *single
+		}
+
+		switch block := block.(type) {
+		case schema.ListNestedBlock:
+			spec[name] = &hcldec.BlockListSpec{
+				TypeName: name,
+				Nested:   objectSpec(block.NestedObject.Attributes, block.NestedObject.Blocks),
+			}
+		case schema.SetNestedBlock:
+			spec[name] = &hcldec.BlockSetSpec{
+				TypeName: name,
+				Nested:   objectSpec(block.NestedObject.Attributes, block.NestedObject.Blocks),
+			}
+		case schema.SingleNestedBlock:
+			spec[name] = &hcldec.BlockSpec{
+				TypeName: name,
+				Nested:   objectSpec(block.Attributes, block.Blocks),
+			}
+		}
+	}
+
+	return spec
+}
+
+func ctyType(t tftypes.Type) cty.Type {
+	switch t := t.(type) {
+	case tftypes.List:
+		return cty.List(ctyType(t.ElementType))
+	case tftypes.Set:
+		return cty.Set(ctyType(t.ElementType))
+	case tftypes.Map:
+		return cty.Map(ctyType(t.ElementType))
+	case tftypes.Object:
+		attrs := map[string]cty.Type{}
+		for name, attr := range t.AttributeTypes {
+			attrs[name] = ctyType(attr)
+		}
+		return cty.Object(attrs)
+	}
+
+	switch {
+	case t.Is(tftypes.String):
+		return cty.String
+	case t.Is(tftypes.Bool):
+		return cty.Bool
+	case t.Is(tftypes.Number):
+		return cty.Number
+	}
+	return cty.DynamicPseudoType
+}
+
+// ctyToTerraform converts a decoded value to the framework's representation
+// of typ. Attributes missing from val, such as computed ones, are null.
+func ctyToTerraform(val cty.Value, typ tftypes.Type) (tftypes.Value, error) {
+	if val.IsNull() {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	if !val.IsWhollyKnown() {
+		return tftypes.Value{}, fmt.Errorf("values must be known without running Terraform")
+	}
+
+	switch t := typ.(type) {
+	case tftypes.List, tftypes.Set:
+		var elemType tftypes.Type
+		if list, ok := t.(tftypes.List); ok {
+			elemType = list.ElementType
+		} else {
+			elemType = t.(tftypes.Set).ElementType
+		}
+
+		elems := []tftypes.Value{}
+		for it := val.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			elem, err := ctyToTerraform(v, elemType)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems = append(elems, elem)
+		}
+		return tftypes.NewValue(typ, elems), nil
+	case tftypes.Map:
+		elems := map[string]tftypes.Value{}
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			elem, err := ctyToTerraform(v, t.ElementType)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems[k.AsString()] = elem
+		}
+		return tftypes.NewValue(typ, elems), nil
+	case tftypes.Object:
+		attrs := map[string]tftypes.Value{}
+		for name, attrType := range t.AttributeTypes {
+			if !val.Type().HasAttribute(name) {
+				attrs[name] = tftypes.NewValue(attrType, nil)
+				continue
+			}
+			attr, err := ctyToTerraform(val.GetAttr(name), attrType)
+			if err != nil {
+				return tftypes.Value{}, fmt.Errorf("%s: %w", name, err)
+			}
+			attrs[name] = attr
+		}
+		return tftypes.NewValue(typ, attrs), nil
+	}
+
+	converted, err := convert.Convert(val, ctyType(typ))
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	switch {
+	case typ.Is(tftypes.String):
+		return tftypes.NewValue(typ, converted.AsString()), nil
+	case typ.Is(tftypes.Bool):
+		return tftypes.NewValue(typ, converted.True()), nil
+	case typ.Is(tftypes.Number):
+		return tftypes.NewValue(typ, converted.AsBigFloat()), nil
+	}
+	return tftypes.Value{}, fmt.Errorf("unsupported type %s", typ)
+}
+
+func fromHCLDiagnostics(hclDiags hcl.Diagnostics) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, d := range hclDiags {
+		detail := d.Detail
+		if d.Subject != nil {
+			detail = d.Subject.String() + ": " + detail
+		}
+		if d.Severity == hcl.DiagError {
+			diags.AddError(d.Summary, detail)
+		} else {
+			diags.AddWarning(d.Summary, detail)
+		}
+	}
+	return diags
+}
+
+// withAddress qualifies a diagnostic with the resource, and attribute path if
+// any, that it's about, since there's no Terraform to do so.
+func withAddress(address string, d diag.Diagnostic) diag.Diagnostic {
+	where := address
+	if d, ok := d.(diag.DiagnosticWithPath); ok && !d.Path().Equal(path.Empty()) {
+		where += "." + d.Path().String()
+	}
+
+	detail := where + ": " + d.Detail()
+	if d.Severity() == diag.SeverityError {
+		return diag.NewErrorDiagnostic(d.Summary(), detail)
+	}
+	return diag.NewWarningDiagnostic(d.Summary(), detail)
+}
@@ -0,0 +1,23 @@
+resource "caiac_go_source" "test" {
+  filename            = "imports.go"
+  package_name        = "imports"
+  local_import_prefix = "example.com/project"
+
+  import {
+    path = "example.com/project/internal/util"
+  }
+  import {
+    path = "github.com/google/go-cmp/cmp"
+  }
+  import {
+    name = "stdjson"
+    path = "encoding/json"
+  }
+  import {
+    name = "_"
+    path = "embed"
+  }
+  import {
+    path = "fmt"
+  }
+}
@@ -0,0 +1,52 @@
+resource "caiac_go_source" "test" {
+  filename             = "imports.go"
+  package_name         = "imports"
+  prune_unused_imports = true
+  add_missing_imports  = true
+
+  import {
+    path = "os"
+  }
+  import {
+    path = "fmt"
+  }
+
+  func {
+    name = "main"
+
+    body {
+      statement {
+        kind = "expression"
+        expression {
+          kind = "call"
+          call {
+            func {
+              from = "fmt"
+              prop = "Println"
+            }
+            arg {
+              kind  = "identifier"
+              value = "strings.ToUpper"
+            }
+          }
+        }
+      }
+      statement {
+        kind = "expression"
+        expression {
+          kind = "call"
+          call {
+            func {
+              from = "strings"
+              prop = "TrimSpace"
+            }
+            arg {
+              kind  = "string"
+              value = " x "
+            }
+          }
+        }
+      }
+    }
+  }
+}
@@ -0,0 +1,44 @@
+# Packages used only in parameter and result types count as used.
+resource "caiac_go_source" "test" {
+  filename             = "imports.go"
+  package_name         = "imports"
+  prune_unused_imports = true
+  add_missing_imports  = true
+
+  import {
+    path = "io"
+  }
+  import {
+    path = "strings"
+  }
+
+  func {
+    name = "handle"
+
+    signature {
+      param {
+        name = "w"
+        type = "io.Writer"
+      }
+      param {
+        name = "t"
+        type = "*testing.T"
+      }
+      result {
+        type = "map[string]http.Handler"
+      }
+    }
+
+    body {
+      statement {
+        kind = "return"
+        expression {
+          kind = "identifier"
+          identifier {
+            name = "nil"
+          }
+        }
+      }
+    }
+  }
+}
@@ -0,0 +1,46 @@
+resource "caiac_go_source" "test" {
+  filename     = "invalid.go"
+  package_name = "invalid"
+
+  func {
+    name = "main"
+
+    body {
+      statement {
+        kind = "assign"
+      }
+      statement {
+        kind = "expression"
+        expression {
+          kind = "composite"
+        }
+      }
+      statement {
+        kind = "expression"
+      }
+      statement {
+        kind = "expression"
+        expression {
+          kind = "call"
+        }
+      }
+      statement {
+        kind = "expression"
+        expression {
+          kind = "call"
+          call {}
+        }
+      }
+      statement {
+        kind = "return"
+        expression {
+          kind = "literal"
+          literal {
+            kind  = "float"
+            value = "1.5"
+          }
+        }
+      }
+    }
+  }
+}
@@ -0,0 +1,61 @@
+resource "caiac_go_source" "test" {
+  filename     = "invalid.go"
+  package_name = "not-a-package"
+
+  import {
+    path = ""
+  }
+
+  func {
+    name = "main"
+
+    signature {
+      param {
+        name = "x"
+      }
+    }
+
+    body {
+      statement {
+        kind = "return"
+        expression {
+          kind = "literal"
+          literal {
+            kind  = "int"
+            value = "forty-two"
+          }
+        }
+      }
+      statement {
+        kind = "return"
+        expression {
+          kind = "identifier"
+          identifier {
+            name = "1x"
+          }
+        }
+      }
+    }
+  }
+
+  func {
+    name = "mixed"
+
+    signature {
+      param {
+        name = "a"
+        type = "int"
+      }
+      param {
+        type = "string"
+      }
+      result {
+        type = "int"
+      }
+      result {
+        name = "err"
+        type = "error"
+      }
+    }
+  }
+}
@@ -0,0 +1,63 @@
+resource "caiac_go_source" "test" {
+  filename     = "literals.go"
+  package_name = "literals"
+
+  import {
+    path = "fmt"
+  }
+
+  func {
+    name = "describe"
+
+    signature {
+      result {
+        type = "string"
+      }
+    }
+
+    body {
+      statement {
+        kind = "expression"
+        expression {
+          kind = "call"
+          call {
+            func {
+              from = "fmt"
+              prop = "Println"
+            }
+            arg {
+              kind  = "string"
+              value = "string"
+            }
+            arg {
+              kind  = "string"
+              value = "say \"hi\"\n\tand C:\\ \u00e9"
+            }
+            arg {
+              kind  = "int"
+              value = "7"
+            }
+            arg {
+              kind  = "int"
+              value = "0x1F"
+            }
+            arg {
+              kind  = "identifier"
+              value = "nil"
+            }
+          }
+        }
+      }
+      statement {
+        kind = "return"
+        expression {
+          kind = "literal"
+          literal {
+            kind  = "string"
+            value = "done"
+          }
+        }
+      }
+    }
+  }
+}
@@ -0,0 +1,94 @@
+resource "caiac_go_source" "test" {
+  filename     = "signature.go"
+  package_name = "signature"
+
+  func {
+    name = "noSignature"
+    body {}
+  }
+
+  func {
+    name = "params"
+
+    signature {
+      param {
+        name = "a"
+        type = "int"
+      }
+      param {
+        name = "b"
+        type = "string"
+      }
+      param {
+        name = "c"
+        type = "bool"
+      }
+    }
+
+    body {}
+  }
+
+  func {
+    name = "unnamedParams"
+
+    signature {
+      param {
+        type = "int"
+      }
+      param {
+        type = "string"
+      }
+    }
+
+    body {}
+  }
+
+  func {
+    name = "results"
+
+    signature {
+      result {
+        name = "n"
+        type = "int"
+      }
+      result {
+        name = "err"
+        type = "error"
+      }
+    }
+
+    body {
+      statement {
+        kind = "return"
+      }
+    }
+  }
+
+  func {
+    name = "unnamedResults"
+
+    signature {
+      result {
+        type = "int"
+      }
+      result {
+        type = "error"
+      }
+    }
+  }
+
+  # Without a body, the function is implemented elsewhere.
+  func {
+    name = "assembly"
+
+    signature {
+      param {
+        name = "x"
+        type = "uint64"
+      }
+      result {
+        type = "uint64"
+      }
+    }
+  }
+}
@@ -0,0 +1,51 @@
+resource "caiac_go_source" "test" {
+  filename     = "main.go"
+  package_name = "main"
+
+  import {
+    path = "fmt"
+  }
+
+  func {
+    name = "main"
+
+    body {
+      statement {
+        kind = "expression"
+        expression {
+          kind = "call"
+          call {
+            func {
+              from = "fmt"
+              prop = "Println"
+            }
+            arg {
+              kind  = "string"
+              value = "Hello, world"
+            }
+          }
+        }
+      }
+      statement {
+        kind = "expression"
+        expression {
+          kind = "call"
+          call {
+            func {
+              prop = "cleanup"
+            }
+          }
+        }
+      }
+      statement {
+        kind = "expression"
+        expression {
+          kind = "identifier"
+          identifier {
+            name = "done"
+          }
+        }
+      }
+    }
+  }
+}
@@ -0,0 +1,141 @@
+resource "caiac_go_source" "test" {
+  filename     = "returns.go"
+  package_name = "returns"
+
+  import {
+    path = "os"
+  }
+
+  func {
+    name = "bare"
+
+    body {
+      statement {
+        kind = "return"
+      }
+    }
+  }
+
+  func {
+    name = "call"
+
+    signature {
+      result {
+        type = "string"
+      }
+    }
+
+    body {
+      statement {
+        kind = "return"
+        expression {
+          kind = "call"
+          call {
+            func {
+              from = "os"
+              prop = "Getenv"
+            }
+            arg {
+              kind  = "string"
+              value = "HOME"
+            }
+          }
+        }
+      }
+    }
+  }
+
+  func {
+    name = "qualified"
+
+    signature {
+      result {
+        type = "*os.File"
+      }
+    }
+
+    body {
+      statement {
+        kind = "return"
+        expression {
+          kind = "selector"
+          selector {
+            from = "os"
+            prop = "Stdout"
+          }
+        }
+      }
+    }
+  }
+
+  func {
+    name = "local"
+
+    signature {
+      result {
+        type = "func()"
+      }
+    }
+
+    body {
+      statement {
+        kind = "return"
+        expression {
+          kind = "selector"
+          selector {
+            prop = "bare"
+          }
+        }
+      }
+    }
+  }
+
+  func {
+    name = "literal"
+
+    signature {
+      result {
+        type = "int"
+      }
+    }
+
+    body {
+      statement {
+        kind = "return"
+        expression {
+          kind = "literal"
+          literal {
+            kind  = "int"
+            value = "42"
+          }
+        }
+      }
+    }
+  }
+
+  func {
+    name = "identifier"
+
+    signature {
+      param {
+        name = "v"
+        type = "bool"
+      }
+      result {
+        type = "bool"
+      }
+    }
+
+    body {
+      statement {
+        kind = "return"
+        expression {
+          kind = "identifier"
+          identifier {
+            name = "v"
+          }
+        }
+      }
+    }
+  }
+}
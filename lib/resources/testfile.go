@@ -0,0 +1,341 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"terraform-provider-caiac/lib/gen"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// testFuncKinds describes each kind of function the go test command runs,
+// keyed by the block declaring them.
+var testFuncKinds = []struct {
+	block  string
+	prefix string
+	param  string
+	typ    string
+}{
+	{block: "test", prefix: "Test", param: "t", typ: "*testing.T"},
+	{block: "benchmark", prefix: "Benchmark", param: "b", typ: "*testing.B"},
+	{block: "fuzz", prefix: "Fuzz", param: "f", typ: "*testing.F"},
+}
+
+// renderGoTest renders a _test.go file. Test, benchmark, fuzz, and example
+// functions are built from the same function model as caiac_go_source, with
+// their signatures fixed by the testing package's conventions.
+func renderGoTest(ctx context.Context, model *goTestResourceModel, diags *diag.Diagnostics) string {
+	ctx = withRenderSubsystem(ctx)
+	ctx = tflog.SubsystemSetField(ctx, renderSubsystem, "filename", model.Filename.ValueString())
+
+	if !strings.HasSuffix(model.Filename.ValueString(), "_test.go") {
+		diags.AddAttributeError(
+			path.Root("filename"),
+			"Invalid test file name",
+			"The go command only runs tests in files whose names end in \"_test.go\".",
+		)
+	}
+
+	pkg := model.PackageName.ValueString()
+	if model.External.ValueBool() {
+		pkg += "_test"
+	}
+
+	file := &gen.File{
+		Package:           pkg,
+		Imports:           append([]TImport{}, model.Imports...),
+		AddMissingImports: model.AddMissingImports.ValueBool(),
+		LocalImportPrefix: model.LocalImportPrefix.ValueString(),
+	}
+	// Only add the imports that generated code uses: examples take no
+	// *testing.T, and tables only compare results with reflect.DeepEqual.
+	if len(model.Tests)+len(model.Benchmarks)+len(model.Fuzzes)+len(model.TableTests) > 0 {
+		file.Imports = append(file.Imports, TImport{Path: "testing"})
+	}
+	for _, table := range model.TableTests {
+		if len(table.Results) > 0 {
+			file.Imports = append(file.Imports, TImport{Path: "reflect"})
+			break
+		}
+	}
+
+	// Each block declares a top-level function, so their names mustn't
+	// clash, whichever kind of block they come from.
+	declared := map[string]path.Path{}
+	declare := func(p path.Path, name string) {
+		if first, ok := declared[name]; ok {
+			diags.AddAttributeError(p.AtName("name"), "Duplicate function name", fmt.Sprintf("%s is already declared by %s.", name, first))
+			return
+		}
+		declared[name] = p
+	}
+
+	// Functions come from several kinds of block, so remember where each
+	// one came from to report problems against the right block.
+	origins := []path.Path{}
+	addFunc := func(p path.Path, fn TFunc) {
+		declare(p, fn.Name)
+		// Unlike ordinary functions, tests always need a body.
+		if fn.Body == nil {
+			fn.Body = &TBody{}
+		}
+		file.Funcs = append(file.Funcs, fn)
+		origins = append(origins, p)
+	}
+
+	for _, kind := range testFuncKinds {
+		var blocks []TTestFunc
+		switch kind.block {
+		case "test":
+			blocks = model.Tests
+		case "benchmark":
+			blocks = model.Benchmarks
+		case "fuzz":
+			blocks = model.Fuzzes
+		}
+
+		for i, block := range blocks {
+			p := path.Root(kind.block).AtListIndex(i)
+			diags.Append(validateTestName(p.AtName("name"), kind.prefix, block.Name)...)
+
+			param, typ := kind.param, kind.typ
+			addFunc(p, TFunc{
+				Name:      kind.prefix + block.Name,
+				Signature: &TSignature{Params: []TField{{Name: &param, Type: &typ}}},
+				Body:      block.Body,
+			})
+		}
+	}
+
+	for i, example := range model.Examples {
+		addFunc(path.Root("example").AtListIndex(i), TFunc{
+			Name:      "Example" + valueOrEmpty(example.Name),
+			Signature: &TSignature{},
+			Body:      example.Body,
+		})
+	}
+
+	tables := new(strings.Builder)
+	for i, table := range model.TableTests {
+		p := path.Root("table_test").AtListIndex(i)
+		diags.Append(validateTestName(p.AtName("name"), "Test", table.Name)...)
+		declare(p, "Test"+table.Name)
+		writeTableTest(tables, p, &table, diags)
+	}
+
+	contents := renderGenFile(ctx, file, func(p gen.Path) (path.Path, gen.Path) {
+		if len(p) >= 2 && p[0].Name == "func" {
+			return atPath(origins[p[1].Index], p[2:]), nil
+		}
+		return atPath(path.Empty(), p), nil
+	}, diags)
+	if diags.HasError() {
+		return ""
+	}
+
+	for i, example := range model.Examples {
+		if example.Output != nil {
+			p := path.Root("example").AtListIndex(i).AtName("output")
+			contents = insertExampleOutput(p, contents, "Example"+valueOrEmpty(example.Name), *example.Output, diags)
+		}
+	}
+	if diags.HasError() {
+		return ""
+	}
+
+	formatted, err := format.Source([]byte(contents + tables.String()))
+	if err != nil {
+		diags.AddError(
+			"Error printing AST",
+			"Unable to format test file: "+err.Error(),
+		)
+		return ""
+	}
+
+	// Table tests are written as text, so handle imports again now that
+	// they're part of the file.
+	if len(model.TableTests) > 0 {
+		formatted, err = file.FixImports(ctx, formatted)
+		if err != nil {
+			diags.AddError(
+				"Error printing AST",
+				"Unable to organize imports of test file: "+err.Error(),
+			)
+			return ""
+		}
+	}
+
+	return string(formatted)
+}
+
+// validateTestName applies the go command's rule that the character after
+// a test function's prefix mustn't be a lowercase letter.
+func validateTestName(p path.Path, prefix string, name string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if r, _ := utf8.DecodeRuneInString(name); unicode.IsLower(r) {
+		diags.AddAttributeError(
+			p,
+			"Invalid test name",
+			fmt.Sprintf("%q would be ignored by the go command: the name after %q must not start with a lowercase letter.", prefix+name, prefix),
+		)
+	}
+	return diags
+}
+
+// insertExampleOutput adds an "// Output:" comment to the end of the named
+// example function's body, reporting an error at p if it can't be found.
+func insertExampleOutput(p path.Path, src string, name string, output string, diags *diag.Diagnostics) string {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.SkipObjectResolution)
+	if err != nil {
+		diags.AddAttributeError(p, "Error adding example output", "Unable to parse rendered test file: "+err.Error())
+		return src
+	}
+
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Name.Name != name || fn.Body == nil {
+			continue
+		}
+
+		comment := new(strings.Builder)
+		end := fset.Position(fn.Body.Rbrace).Offset
+		if end == 0 || src[end-1] != '\n' {
+			comment.WriteString("\n")
+		}
+		comment.WriteString("\t// Output:\n")
+		for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+			if line == "" {
+				comment.WriteString("\t//\n")
+				continue
+			}
+			comment.WriteString("\t// " + line + "\n")
+		}
+		return src[:end] + comment.String() + src[end:]
+	}
+
+	diags.AddAttributeError(p, "Error adding example output", fmt.Sprintf("Unable to find %s in the rendered test file.", name))
+	return src
+}
+
+// writeTableTest writes a table-driven test to w: a slice of cases with a
+// field per parameter and result, and a loop running each case as a subtest.
+func writeTableTest(w *strings.Builder, p path.Path, table *TTableTest, diags *diag.Diagnostics) {
+	if _, err := parser.ParseExpr(table.Func); err != nil {
+		diags.AddAttributeError(p.AtName("func"), "Invalid function", fmt.Sprintf("%q is not a valid Go expression: %s", table.Func, err))
+	}
+
+	type column struct {
+		field string
+		typ   string
+	}
+	columns := func(block string, fields []TField, prefix string) []column {
+		cols := []column{}
+		for i, field := range fields {
+			fp := p.AtName(block).AtListIndex(i)
+			if field.Type == nil {
+				diags.AddAttributeError(fp.AtName("type"), "Missing field type", "Table test parameters and results must declare a type.")
+				continue
+			}
+
+			if field.Name != nil && (!token.IsIdentifier(*field.Name) || *field.Name == "_") {
+				diags.AddAttributeError(fp.AtName("name"), "Invalid identifier", fmt.Sprintf("%q is not a valid Go identifier for a field each case can set.", *field.Name))
+				continue
+			}
+
+			name := prefix
+			switch {
+			case field.Name != nil && prefix == "":
+				name = *field.Name
+			case field.Name != nil:
+				r, size := utf8.DecodeRuneInString(*field.Name)
+				name = prefix + string(unicode.ToUpper(r)) + (*field.Name)[size:]
+			case prefix == "":
+				name = fmt.Sprintf("arg%d", i)
+			case i > 0:
+				name = fmt.Sprintf("%s%d", prefix, i)
+			}
+			if name == "name" {
+				diags.AddAttributeError(fp.AtName("name"), "Reserved field name", "\"name\" is used for each case's name.")
+			}
+			cols = append(cols, column{field: name, typ: *field.Type})
+		}
+		return cols
+	}
+	params := columns("param", table.Params, "")
+	results := columns("result", table.Results, "want")
+
+	for i, c := range table.Cases {
+		cp := p.AtName("case").AtListIndex(i)
+		if len(c.Args) != len(table.Params) {
+			diags.AddAttributeError(cp.AtName("args"), "Wrong number of arguments", fmt.Sprintf("Expected %d arguments, got %d.", len(table.Params), len(c.Args)))
+		}
+		if len(c.Want) != len(table.Results) {
+			diags.AddAttributeError(cp.AtName("want"), "Wrong number of results", fmt.Sprintf("Expected %d results, got %d.", len(table.Results), len(c.Want)))
+		}
+		for j, expr := range append(append([]string{}, c.Args...), c.Want...) {
+			if _, err := parser.ParseExpr(expr); err != nil {
+				attr, k := "args", j
+				if j >= len(c.Args) {
+					attr, k = "want", j-len(c.Args)
+				}
+				diags.AddAttributeError(cp.AtName(attr).AtListIndex(k), "Invalid expression", fmt.Sprintf("%q is not a valid Go expression: %s", expr, err))
+			}
+		}
+	}
+
+	if diags.HasError() {
+		return
+	}
+
+	fmt.Fprintf(w, "\nfunc Test%s(t *testing.T) {\n\ttests := []struct {\n\t\tname string\n", table.Name)
+	for _, col := range append(append([]column{}, params...), results...) {
+		fmt.Fprintf(w, "\t\t%s %s\n", col.field, col.typ)
+	}
+	w.WriteString("\t}{\n")
+	for _, c := range table.Cases {
+		fmt.Fprintf(w, "\t\t{\n\t\t\tname: %s,\n", strconv.Quote(c.Name))
+		for j, arg := range c.Args {
+			fmt.Fprintf(w, "\t\t\t%s: %s,\n", params[j].field, arg)
+		}
+		for j, want := range c.Want {
+			fmt.Fprintf(w, "\t\t\t%s: %s,\n", results[j].field, want)
+		}
+		w.WriteString("\t\t},\n")
+	}
+	w.WriteString("\t}\n\n\tfor _, tt := range tests {\n\t\tt.Run(tt.name, func(t *testing.T) {\n")
+
+	args := []string{}
+	for _, col := range params {
+		args = append(args, "tt."+col.field)
+	}
+	call := table.Func + "(" + strings.Join(args, ", ") + ")"
+
+	if len(results) == 0 {
+		fmt.Fprintf(w, "\t\t\t%s\n", call)
+	} else {
+		gots := []string{}
+		for _, col := range results {
+			gots = append(gots, "got"+strings.TrimPrefix(col.field, "want"))
+		}
+		fmt.Fprintf(w, "\t\t\t%s := %s\n", strings.Join(gots, ", "), call)
+		for j, col := range results {
+			fmt.Fprintf(w, "\t\t\tif !reflect.DeepEqual(%s, tt.%s) {\n", gots[j], col.field)
+			fmt.Fprintf(w, "\t\t\t\tt.Errorf(\"%s() %s = %%v, want %%v\", %s, tt.%s)\n", strings.ReplaceAll(table.Func, `"`, `\"`), gots[j], gots[j], col.field)
+			w.WriteString("\t\t\t}\n")
+		}
+	}
+	w.WriteString("\t\t})\n\t}\n}\n")
+}
@@ -0,0 +1,180 @@
+package resources
+
+import (
+	"context"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// TestRenderGoTestCompiles renders test files that don't use every import
+// the generated code can need, and checks that go test accepts them.
+func TestRenderGoTestCompiles(t *testing.T) {
+	fmtPkg := "fmt"
+	s, typ := "s", "string"
+	output, empty, shout := "hi", "", "_shout"
+	println := &TBody{Statements: []TStatement{{
+		Kind: "expression",
+		Expr: &TExpression{Kind: "call", Call: &TCall{
+			Func: &TSelector{From: &fmtPkg, Prop: "Println"},
+			Args: []TLiteral{{Kind: "string", Value: "hi"}},
+		}},
+	}}}
+
+	tests := []struct {
+		name  string
+		model goTestResourceModel
+	}{
+		{
+			name: "examples only",
+			model: goTestResourceModel{
+				Imports:  []TImport{{Path: "fmt"}},
+				Examples: []TExample{{Output: &output, Body: println}},
+			},
+		},
+		{
+			name: "examples with empty bodies",
+			model: goTestResourceModel{
+				Examples: []TExample{{Output: &empty}, {Name: &shout, Output: &empty, Body: &TBody{}}},
+			},
+		},
+		{
+			name: "table of a package function",
+			model: goTestResourceModel{
+				AddMissingImports: types.BoolValue(true),
+				TableTests: []TTableTest{{
+					Name:    "ToUpper",
+					Func:    "strings.ToUpper",
+					Params:  []TField{{Name: &s, Type: &typ}},
+					Results: []TField{{Type: &typ}},
+					Cases:   []TTestCase{{Name: "hi", Args: []string{`"hi"`}, Want: []string{`"HI"`}}},
+				}},
+			},
+		},
+		{
+			name: "table without results",
+			model: goTestResourceModel{
+				TableTests: []TTableTest{{
+					Name:   "Shout",
+					Func:   "Shout",
+					Params: []TField{{Name: &s, Type: &typ}},
+					Cases:  []TTestCase{{Name: "hi", Args: []string{`"hi"`}}},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.model.Filename = types.StringValue("p_test.go")
+			tt.model.PackageName = types.StringValue("p")
+
+			var diags diag.Diagnostics
+			contents := renderGoTest(context.Background(), &tt.model, &diags)
+			if diags.HasError() {
+				t.Fatalf("rendering failed:\n%s", formatDiagnostics(diags))
+			}
+
+			// go test only checks the output of examples whose output
+			// comment go/doc recognises.
+			f, err := parser.ParseFile(token.NewFileSet(), "p_test.go", contents, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			examples := doc.Examples(f)
+			if len(examples) != len(tt.model.Examples) {
+				t.Fatalf("found %d examples, want %d in:\n%s", len(examples), len(tt.model.Examples), contents)
+			}
+			for i, example := range examples {
+				want := strings.TrimSpace(*tt.model.Examples[i].Output)
+				if strings.TrimSpace(example.Output) != want || want == "" && !example.EmptyOutput {
+					t.Errorf("Example%s has output %q, want %q in:\n%s", example.Name, example.Output, want, contents)
+				}
+			}
+
+			testGoPackage(t, map[string]string{
+				"p.go":      "package p\n\nfunc Shout(s string) {}\n",
+				"p_test.go": contents,
+			})
+		})
+	}
+}
+
+// TestRenderGoTestInvalidFieldNames checks that table fields which can't be
+// Go identifiers are reported against their block rather than rendered.
+func TestRenderGoTestInvalidFieldNames(t *testing.T) {
+	for _, name := range []string{"", "_", "1st", "a-b"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			typ := "int"
+			model := goTestResourceModel{
+				Filename:    types.StringValue("p_test.go"),
+				PackageName: types.StringValue("p"),
+				TableTests: []TTableTest{{
+					Name:    "Len",
+					Func:    "len",
+					Params:  []TField{{Name: &name, Type: &typ}},
+					Results: []TField{{Name: &name, Type: &typ}},
+				}},
+			}
+
+			var diags diag.Diagnostics
+			renderGoTest(context.Background(), &model, &diags)
+
+			want := "table_test[0].param[0].name: Error: Invalid identifier: " + strconv.Quote(name) + " is not a valid Go identifier for a field each case can set.\n" +
+				"table_test[0].result[0].name: Error: Invalid identifier: " + strconv.Quote(name) + " is not a valid Go identifier for a field each case can set.\n"
+			if got := formatDiagnostics(diags); got != want {
+				t.Errorf("got diagnostics:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+// TestRenderGoTestDuplicateNames checks that functions declared by different
+// blocks can't share a name.
+func TestRenderGoTestDuplicateNames(t *testing.T) {
+	model := goTestResourceModel{
+		Filename:    types.StringValue("p_test.go"),
+		PackageName: types.StringValue("p"),
+		Tests:       []TTestFunc{{Name: "Len"}},
+		TableTests:  []TTableTest{{Name: "Len", Func: "len"}},
+	}
+
+	var diags diag.Diagnostics
+	renderGoTest(context.Background(), &model, &diags)
+
+	want := "table_test[0].name: Error: Duplicate function name: TestLen is already declared by test[0].\n"
+	if got := formatDiagnostics(diags); got != want {
+		t.Errorf("got diagnostics:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// testGoPackage runs go test on a module holding files, failing t if it
+// doesn't compile or pass.
+func testGoPackage(t *testing.T, files map[string]string) {
+	t.Helper()
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/p\n\ngo 1.19\n"
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if out, err := runGo(context.Background(), dir, nil, "test", "-count=1", "."); err != nil {
+		t.Errorf("go test failed: %s\n%s\nfor:\n%s", err, out, files["p_test.go"])
+	}
+}
@@ -0,0 +1,47 @@
+package resources
+
+import (
+	"context"
+
+	"terraform-provider-caiac/lib/gen"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// renderSubsystem is the tflog subsystem used while converting HCL to Go AST
+// nodes, so `TF_LOG=DEBUG` shows which block produced each node.
+const renderSubsystem = "caiac.render"
+
+func withRenderSubsystem(ctx context.Context) context.Context {
+	return tflog.NewSubsystem(ctx, renderSubsystem)
+}
+
+// withRenderTracer logs gen's progress to the render subsystem, reporting
+// the HCL path of each node via locate.
+func withRenderTracer(ctx context.Context, locate locator) context.Context {
+	return gen.WithTracer(ctx, func(msg string, fields map[string]interface{}) {
+		if p, ok := fields["path"].(gen.Path); ok {
+			delete(fields, "path")
+			attr, rest := locate(p)
+			fields["hcl_path"] = attr.String()
+			if len(rest) > 0 {
+				fields["json_path"] = rest.String()
+			}
+		}
+		tflog.SubsystemDebug(ctx, renderSubsystem, msg, fields)
+	})
+}
+
+// atPath converts a path within a gen.File to the configuration path of the
+// corresponding block, relative to base.
+func atPath(base path.Path, p gen.Path) path.Path {
+	for _, step := range p {
+		if step.Name == "" {
+			base = base.AtListIndex(step.Index)
+			continue
+		}
+		base = base.AtName(step.Name)
+	}
+	return base
+}
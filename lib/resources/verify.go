@@ -0,0 +1,193 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// compilerMessage matches the "file:line:col: msg" lines printed by go build
+// and go vet. Vet prefixes type-checking errors with "vet: ".
+var compilerMessage = regexp.MustCompile(`^(?:vet: )?(\S+\.go):(\d+)(?::\d+)?: (.*)$`)
+
+// snapshotFile records a file's content before it's overwritten, so that
+// restoreFile can put it back.
+type snapshotFile struct {
+	filename string
+	contents []byte
+	existed  bool
+}
+
+func takeSnapshot(filename string, diags *diag.Diagnostics) *snapshotFile {
+	contents, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &snapshotFile{filename: filename}
+	}
+	if err != nil {
+		diags.AddError(
+			"Error reading file",
+			"Unable to read previous content before writing: "+err.Error(),
+		)
+		return nil
+	}
+	return &snapshotFile{filename: filename, contents: contents, existed: true}
+}
+
+// restore writes back the snapshotted content, or removes the file and any
+// directories created for it if it didn't exist before.
+func (s *snapshotFile) restore(ctx context.Context, stop string, diags *diag.Diagnostics) {
+	tflog.Debug(ctx, "Rolling back file", map[string]interface{}{"filename": s.filename, "existed": s.existed})
+
+	if s.existed {
+		if err := os.WriteFile(s.filename, s.contents, os.ModePerm); err != nil {
+			diags.AddError(
+				"Error rolling back file",
+				"Unable to restore previous content after failed verification: "+err.Error(),
+			)
+		}
+		return
+	}
+
+	if err := os.Remove(s.filename); err != nil {
+		diags.AddError(
+			"Error rolling back file",
+			"Unable to remove file after failed verification: "+err.Error(),
+		)
+		return
+	}
+	removeEmptyDirs(ctx, filepath.Dir(s.filename), stop, diags)
+}
+
+// verifyGoSource runs go build and then go vet on the package containing
+// filename, which has just been rendered from model. Problems in filename are
+// reported against the import or func block, or statement, that produced the
+// offending line; problems elsewhere in the package are reported as is.
+func verifyGoSource(ctx context.Context, filename string, model *goSourceResourceModel, diags *diag.Diagnostics) {
+	dir := filepath.Dir(filename)
+
+	for _, args := range [][]string{{"build", "-o", os.DevNull, "."}, {"vet", "."}} {
+		output, err := runGo(ctx, dir, nil, args...)
+		if err == nil {
+			continue
+		}
+
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			diags.AddError(
+				"Unable to run go "+args[0],
+				"Make sure a Go toolchain is installed and on the provider's PATH: "+err.Error(),
+			)
+			return
+		}
+
+		reportCompilerOutput(filename, model, "go "+args[0], output, diags)
+		return
+	}
+}
+
+func reportCompilerOutput(filename string, model *goSourceResourceModel, tool string, output string, diags *diag.Diagnostics) {
+	locate := blockLocator(filename, model)
+	reported := false
+
+	for _, line := range strings.Split(output, "\n") {
+		m := compilerMessage.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		reported = true
+
+		summary := tool + " failed"
+		if filepath.Base(m[1]) != filepath.Base(filename) {
+			diags.AddError(summary, line)
+			continue
+		}
+
+		lineNo, _ := strconv.Atoi(m[2])
+		if p, ok := locate(lineNo); ok {
+			diags.AddAttributeError(p, summary, fmt.Sprintf("%s:%s: %s", filepath.Base(filename), m[2], m[3]))
+			continue
+		}
+		diags.AddError(summary, line)
+	}
+
+	// Fall back to the raw output for failures that aren't about the code,
+	// such as a missing go.mod.
+	if !reported {
+		diags.AddError(tool+" failed", output)
+	}
+}
+
+// blockLocator parses the rendered file and returns a function mapping a
+// line number to the configuration block that produced it. Imports are
+// matched by path, and functions by position, since they're printed in the
+// order they're declared, followed by any from definition_json.
+func blockLocator(filename string, model *goSourceResourceModel) func(line int) (path.Path, bool) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return func(int) (path.Path, bool) { return path.Empty(), false }
+	}
+
+	contains := func(n ast.Node, line int) bool {
+		return fset.Position(n.Pos()).Line <= line && line <= fset.Position(n.End()).Line
+	}
+
+	return func(line int) (path.Path, bool) {
+		for _, spec := range f.Imports {
+			if !contains(spec, line) {
+				continue
+			}
+			for i, imp := range model.Imports {
+				if strconv.Quote(imp.Path) == spec.Path.Value {
+					return path.Root("import").AtListIndex(i), true
+				}
+			}
+			if !model.DefinitionJSON.IsNull() {
+				return path.Root("definition_json"), true
+			}
+		}
+
+		i := 0
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			p := path.Root("func").AtListIndex(i)
+			i++
+			if !contains(fn, line) {
+				continue
+			}
+
+			// Functions after those from blocks came from the JSON definition.
+			if i > len(model.Funcs) {
+				return path.Root("definition_json"), true
+			}
+
+			if fn.Body != nil {
+				for j, stmt := range fn.Body.List {
+					if contains(stmt, line) {
+						return p.AtName("body").AtName("statement").AtListIndex(j), true
+					}
+				}
+			}
+			return p, true
+		}
+
+		return path.Empty(), false
+	}
+}
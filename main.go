@@ -2,13 +2,31 @@ package main
 
 import (
 	"context"
+	"os"
 	"terraform-provider-caiac/lib"
+	"terraform-provider-caiac/lib/gen"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 )
 
+//go:generate go run . docs
+
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "render":
+			os.Exit(render(context.Background(), os.Args[2:]))
+		case "docs":
+			os.Exit(generateDocs(context.Background(), os.Args[2:]))
+		case "schema":
+			// The JSON Schema for definition_json, published as
+			// schema/go_source.schema.json.
+			os.Stdout.Write(gen.JSONSchema())
+			return
+		}
+	}
+
 	providerserver.Serve(context.Background(), caiac.New, providerserver.ServeOpts{
 		Address: "registry.terraform.io/sjbarag/caiac",
 	})
-}
\ No newline at end of file
+}
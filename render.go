@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"terraform-provider-caiac/lib/resources"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const renderUsage = `Usage: terraform-provider-caiac render [flags] [path ...]
+
+Render the caiac_go_source, caiac_go_test, caiac_go_package, and
+caiac_go_module resources in Terraform configuration without running
+Terraform. Each path is a .tf or .tf.json file, or a directory whose
+configuration files are all read. Defaults to the current directory.
+
+Flags:
+`
+
+// render implements the render subcommand, returning the process's exit
+// code. Nothing is written unless every resource renders successfully, and
+// no file would replace one the provider didn't generate.
+func render(ctx context.Context, args []string) int {
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), renderUsage)
+		flags.PrintDefaults()
+	}
+	baseDir := flags.String("base-dir", ".", "the directory rendered filenames are relative to, as with the provider's base_dir")
+	check := flags.Bool("check", false, "don't write anything; exit with status 1 if any file is out of date")
+	overwrite := flags.Bool("overwrite", false, "replace existing files even if they weren't generated by this provider, or have changed since, as with each resource's overwrite")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	filenames, err := configFiles(paths)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	files, diags := resources.RenderConfig(ctx, filenames)
+	printDiagnostics(diags)
+	if diags.HasError() {
+		return 1
+	}
+
+	if *check {
+		stale := false
+		for _, file := range files {
+			filename := filepath.Join(*baseDir, file.Filename)
+			if existing, err := os.ReadFile(filename); err != nil || string(existing) != file.Contents {
+				fmt.Printf("%s: %s is out of date\n", file.Address, filename)
+				stale = true
+			}
+		}
+		if stale {
+			return 1
+		}
+		return 0
+	}
+
+	// Check every file before writing any, so that a refusal doesn't leave
+	// the configuration half-rendered.
+	var checkDiags diag.Diagnostics
+	for _, file := range files {
+		checkDiags.Append(file.CheckOverwrite(filepath.Join(*baseDir, file.Filename), *overwrite)...)
+	}
+	printDiagnostics(checkDiags)
+	if checkDiags.HasError() {
+		return 1
+	}
+
+	for _, file := range files {
+		filename := filepath.Join(*baseDir, file.Filename)
+
+		if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", file.Address, err)
+			return 1
+		}
+		if err := os.WriteFile(filename, []byte(file.Contents), os.ModePerm); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", file.Address, err)
+			return 1
+		}
+		fmt.Printf("%s: wrote %s\n", file.Address, filename)
+	}
+	return 0
+}
+
+// configFiles expands directories into the Terraform configuration files
+// they contain, in the same way Terraform loads a module.
+func configFiles(paths []string) ([]string, error) {
+	filenames := []string{}
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			filenames = append(filenames, p)
+			continue
+		}
+
+		entries, err := os.ReadDir(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if !entry.IsDir() && (strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tf.json")) {
+				filenames = append(filenames, filepath.Join(p, name))
+			}
+		}
+	}
+	sort.Strings(filenames)
+	return filenames, nil
+}
+
+func printDiagnostics(diags diag.Diagnostics) {
+	for _, d := range diags {
+		severity := "Warning"
+		if d.Severity() == diag.SeverityError {
+			severity = "Error"
+		}
+		fmt.Fprintf(os.Stderr, "%s: %s\n\n  %s\n\n", severity, d.Summary(), d.Detail())
+	}
+}